@@ -4097,6 +4097,11 @@ func init() {
           "description": "Whether or not multi-tenancy is enabled for this class",
           "type": "boolean",
           "x-omitempty": false
+        },
+        "normalizeTenantNames": {
+          "description": "Tenant names should (not) be normalized to lower case when tenants are created and looked up",
+          "type": "boolean",
+          "x-omitempty": false
         }
       }
     },
@@ -9650,6 +9655,11 @@ func init() {
           "description": "Whether or not multi-tenancy is enabled for this class",
           "type": "boolean",
           "x-omitempty": false
+        },
+        "normalizeTenantNames": {
+          "description": "Tenant names should (not) be normalized to lower case when tenants are created and looked up",
+          "type": "boolean",
+          "x-omitempty": false
         }
       }
     },
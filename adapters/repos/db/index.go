@@ -171,6 +171,9 @@ type Index struct {
 	replicator                *replica.Replicator
 
 	partitioningEnabled bool
+	// normalizeTenantNames mirrors the immutable multi-tenancy setting of the
+	// class, tenant names are looked up lower cased if it is enabled
+	normalizeTenantNames bool
 
 	invertedIndexConfig     schema.InvertedIndexConfig
 	invertedIndexConfigLock sync.Mutex
@@ -268,6 +271,7 @@ func NewIndex(ctx context.Context, cfg IndexConfig,
 		stopwords:              sd,
 		replicator:             repl,
 		partitioningEnabled:    shardState.PartitioningEnabled,
+		normalizeTenantNames:   schema.TenantNameNormalizationEnabled(class),
 		remote:                 sharding.NewRemoteIndex(cfg.ClassName.String(), sg, nodeResolver, remoteClient),
 		metrics:                NewMetrics(logger, promMetrics, cfg.ClassName.String(), "n/a"),
 		centralJobQueue:        jobQueueCh,
@@ -607,6 +611,14 @@ func indexID(class schema.ClassName) string {
 	return strings.ToLower(string(class))
 }
 
+// tenantShardName returns the name of the shard that holds tenant
+func (i *Index) tenantShardName(tenant string) string {
+	if i.normalizeTenantNames {
+		return strings.ToLower(tenant)
+	}
+	return tenant
+}
+
 func (i *Index) determineObjectShard(id strfmt.UUID, tenant string) (string, error) {
 	return i.determineObjectShardByStatus(id, tenant, nil)
 }
@@ -633,9 +645,9 @@ func (i *Index) determineObjectShardByStatus(id strfmt.UUID, tenant string, shar
 		}
 	}
 
-	if status := shardsStatus[tenant]; status != "" {
+	if status := shardsStatus[i.tenantShardName(tenant)]; status != "" {
 		if status == models.TenantActivityStatusHOT {
-			return tenant, nil
+			return i.tenantShardName(tenant), nil
 		}
 		return "", objects.NewErrMultiTenancy(fmt.Errorf("%w: '%s'", enterrors.ErrTenantNotActive, tenant))
 	}
@@ -1539,9 +1551,10 @@ func (i *Index) targetShardNames(tenant string) ([]string, error) {
 		return nil, err
 	}
 
-	if tenantShards[tenant] != "" {
-		if tenantShards[tenant] == models.TenantActivityStatusHOT {
-			return []string{tenant}, nil
+	shardName := i.tenantShardName(tenant)
+	if tenantShards[shardName] != "" {
+		if tenantShards[shardName] == models.TenantActivityStatusHOT {
+			return []string{shardName}, nil
 		}
 		return []string{}, objects.NewErrMultiTenancy(fmt.Errorf("%w: '%s'", enterrors.ErrTenantNotActive, tenant))
 	}
@@ -0,0 +1,91 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/weaviate/entities/models"
+	schemaUC "github.com/weaviate/weaviate/usecases/schema"
+)
+
+// tenantStatusGetter answers tenant status lookups like the schema manager
+// does, keyed by the name under which the tenant is stored
+type tenantStatusGetter struct {
+	schemaUC.SchemaGetter
+	normalize bool
+	status    map[string]string
+}
+
+func (g *tenantStatusGetter) ReadOnlyClass(class string) *models.Class {
+	return &models.Class{Class: class}
+}
+
+func (g *tenantStatusGetter) TenantsShards(class string, tenants ...string) (map[string]string, error) {
+	res := map[string]string{}
+	for _, tenant := range tenants {
+		if g.normalize {
+			tenant = strings.ToLower(tenant)
+		}
+		if status, ok := g.status[tenant]; ok {
+			res[tenant] = status
+		}
+	}
+	return res, nil
+}
+
+func (g *tenantStatusGetter) OptimisticTenantStatus(class string, tenant string) (map[string]string, error) {
+	return g.TenantsShards(class, tenant)
+}
+
+func TestIndexTenantShardNames(t *testing.T) {
+	newIndex := func(normalize bool) *Index {
+		return &Index{
+			Config: IndexConfig{ClassName: "C"},
+			getSchema: &tenantStatusGetter{normalize: normalize, status: map[string]string{
+				"tenant1": models.TenantActivityStatusHOT,
+				"tenant2": models.TenantActivityStatusCOLD,
+			}},
+			partitioningEnabled:  true,
+			normalizeTenantNames: normalize,
+		}
+	}
+
+	t.Run("normalized", func(t *testing.T) {
+		idx := newIndex(true)
+
+		shards, err := idx.targetShardNames("Tenant1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tenant1"}, shards)
+
+		shard, err := idx.determineObjectShard("", "TENANT1")
+		require.NoError(t, err)
+		assert.Equal(t, "tenant1", shard)
+
+		_, err = idx.targetShardNames("Tenant2")
+		assert.ErrorContains(t, err, "not active")
+	})
+
+	t.Run("not normalized", func(t *testing.T) {
+		idx := newIndex(false)
+
+		shards, err := idx.targetShardNames("tenant1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tenant1"}, shards)
+
+		_, err = idx.determineObjectShard("", "Tenant1")
+		assert.ErrorContains(t, err, "not found")
+	})
+}
@@ -12,6 +12,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/weaviate/weaviate/entities/models"
 	"github.com/weaviate/weaviate/entities/versioned"
 	"github.com/weaviate/weaviate/usecases/sharding"
@@ -31,6 +33,128 @@ type AddPropertyRequest struct {
 	Properties []*models.Property
 }
 
+// UpdateTenantTombstoneTTLRequest sets how long deleted tenants can't be created again,
+// a non-positive TTL disables tombstones
+type UpdateTenantTombstoneTTLRequest struct {
+	TTL time.Duration
+}
+
+// UpdateTenantReplicationFactorRequest overrides the class replication factor for Tenant,
+// a Factor of 0 removes the override. The Factor must not exceed the number of ClusterNodes.
+type UpdateTenantReplicationFactorRequest struct {
+	Tenant       string
+	Factor       int64
+	ClusterNodes []string
+}
+
+// SuspendTenantCreationRequest blocks or unblocks the creation of new tenants
+type SuspendTenantCreationRequest struct {
+	Suspend bool
+}
+
+// SetNodeTenantsStatusRequest sets the activity status of the tenants whose primary owner is Node
+type SetNodeTenantsStatusRequest struct {
+	Node   string
+	Status string
+}
+
+// SetTenantNodesRequest replaces the nodes Tenant belongs to by Nodes
+type SetTenantNodesRequest struct {
+	Tenant string
+	Nodes  []string
+}
+
+// MergeTenantMetadataRequest sets the labels of Updates on the metadata of their tenant
+type MergeTenantMetadataRequest struct {
+	Updates map[string]map[string]string
+}
+
+// DedupeShardOwnersRequest removes duplicate nodes from the node list of Shards
+type DedupeShardOwnersRequest struct {
+	Shards []string
+}
+
+// RepairBlankPrimariesRequest promotes the first non-blank node of Shards to primary
+type RepairBlankPrimariesRequest struct {
+	Shards []string
+}
+
+// NormalizeEmptyStatusesRequest sets the status of Tenants with an empty status to DefaultStatus
+type NormalizeEmptyStatusesRequest struct {
+	Tenants       []string
+	DefaultStatus string
+}
+
+// RemapNodesRequest replaces the nodes of the shards which are keys of Mapping by their mapped node
+type RemapNodesRequest struct {
+	Mapping map[string]string
+}
+
+// ResetTenantRequest replaces the shard of Tenant by a fresh shard with Status and Nodes
+type ResetTenantRequest struct {
+	Tenant string
+	Status string
+	Nodes  []string
+}
+
+// ShardMove moves the primary ownership of Shard from node From to node To
+type ShardMove struct {
+	Shard string
+	From  string
+	To    string
+}
+
+// ApplyRebalancePlanRequest applies all Moves of a rebalance plan at once
+type ApplyRebalancePlanRequest struct {
+	Moves []ShardMove
+}
+
+// AddShardRequest adds the shard Shard owned by Nodes with Status
+type AddShardRequest struct {
+	Shard  string
+	Nodes  []string
+	Status string
+}
+
+// RemoveShardRequest removes the shard Shard
+type RemoveShardRequest struct {
+	Shard string
+}
+
+// AddReplicasRequest appends the nodes of Replicas to the nodes of their shard
+type AddReplicasRequest struct {
+	Replicas map[string][]string
+}
+
+// TenantSpec describes a tenant to be created by an ApplyTenantBatchRequest
+type TenantSpec struct {
+	Name   string
+	Status string
+	Nodes  []string
+}
+
+// ApplyTenantBatchRequest applies tenant operations of mixed types at once
+type ApplyTenantBatchRequest struct {
+	Creates []TenantSpec
+	Deletes []string
+	// Updates maps tenant names to their new status
+	Updates            map[string]string
+	SkipPlacementCheck bool
+}
+
+// DedupePropertiesRequest removes the properties whose name was already used by a previous property
+type DedupePropertiesRequest struct{}
+
+// SetMaxPropertyCountRequest sets the maximum number of properties of a class, 0 removes the limit
+type SetMaxPropertyCountRequest struct {
+	Limit int
+}
+
+// SetMaxTenantDeletesRequest sets the maximum number of tenants deleted by a single request, 0 removes the limit
+type SetMaxTenantDeletesRequest struct {
+	Limit int
+}
+
 type DeleteClassRequest struct {
 	Name string
 }
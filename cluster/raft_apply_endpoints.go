@@ -14,6 +14,8 @@ package cluster
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	cmd "github.com/weaviate/weaviate/cluster/proto/api"
@@ -153,6 +155,91 @@ func (s *Raft) UpdateTenants(class string, req *cmd.UpdateTenantsRequest) (uint6
 	return s.Execute(command)
 }
 
+// UpdateTenantsWithReason updates the tenants of req like UpdateTenants and records reason
+// in the status history of every changed tenant
+func (s *Raft) UpdateTenantsWithReason(class string, req *cmd.UpdateTenantsRequest, reason string) (uint64, error) {
+	if req == nil {
+		return 0, fmt.Errorf("nil request : %w", schema.ErrBadRequest)
+	}
+	req.Reason = reason
+	return s.UpdateTenants(class, req)
+}
+
+func (s *Raft) UpdateTenantStatusIfOwner(class, owner, tenant, status string) (uint64, error) {
+	if owner == "" || tenant == "" {
+		return 0, fmt.Errorf("empty owner or tenant name : %w", schema.ErrBadRequest)
+	}
+	return s.UpdateTenants(class, &cmd.UpdateTenantsRequest{
+		Tenants: []*cmd.Tenant{{Name: tenant, Status: status}},
+		Owner:   owner,
+	})
+}
+
+// RestoreTenantStatuses restores the tenant statuses of snapshot, taken by SchemaReader.SnapshotStatuses,
+// through UpdateTenants. Transitions which aren't allowed are reported like for UpdateTenants.
+func (s *Raft) RestoreTenantStatuses(class string, snapshot map[string]string) (uint64, error) {
+	req, err := s.SchemaReader().RestoreStatusesRequest(class, snapshot)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", schema.ErrBadRequest, err)
+	}
+	return s.UpdateTenants(class, req)
+}
+
+// FreezeTenants freezes the specified tenants of class through UpdateTenants. Tenants which are
+// already frozen are skipped. It returns the number of tenants frozen and the tenants which don't exist.
+func (s *Raft) FreezeTenants(class string, names []string) (frozen int, missing []string, err error) {
+	if class == "" || len(names) == 0 {
+		return 0, nil, fmt.Errorf("empty class name or tenant list : %w", schema.ErrBadRequest)
+	}
+	tenants, missing, err := s.SchemaReader().FreezeCandidates(class, names)
+	if err != nil {
+		return 0, nil, err
+	}
+	frozen, err = s.freezeTenants(class, tenants)
+	return frozen, missing, err
+}
+
+// FreezeTenantsOnNode freezes every tenant of class whose primary owner is node through UpdateTenants.
+// Tenants which are already frozen are skipped. It returns the number of tenants frozen.
+func (s *Raft) FreezeTenantsOnNode(class, node string) (frozen int, err error) {
+	if class == "" || node == "" {
+		return 0, fmt.Errorf("empty class name or node : %w", schema.ErrBadRequest)
+	}
+	tenants, err := s.SchemaReader().FreezeCandidatesOnNode(class, node)
+	if err != nil {
+		return 0, err
+	}
+	return s.freezeTenants(class, tenants)
+}
+
+// FreezeTenantsByLabel freezes every tenant of class whose metadata maps key to value through UpdateTenants.
+// Tenants without the label and tenants which are already frozen are skipped. It returns the number of tenants frozen.
+func (s *Raft) FreezeTenantsByLabel(class, key, value string) (frozen int, err error) {
+	if class == "" || key == "" {
+		return 0, fmt.Errorf("empty class name or label : %w", schema.ErrBadRequest)
+	}
+	tenants, err := s.SchemaReader().FreezeCandidatesByLabel(class, key, value)
+	if err != nil {
+		return 0, err
+	}
+	return s.freezeTenants(class, tenants)
+}
+
+// freezeTenants updates tenants to FROZEN and returns the number of tenants frozen
+func (s *Raft) freezeTenants(class string, tenants []string) (int, error) {
+	if len(tenants) == 0 {
+		return 0, nil
+	}
+	req := &cmd.UpdateTenantsRequest{Tenants: make([]*cmd.Tenant, len(tenants))}
+	for i, name := range tenants {
+		req.Tenants[i] = &cmd.Tenant{Name: name, Status: models.TenantActivityStatusFROZEN}
+	}
+	if _, err := s.UpdateTenants(class, req); err != nil {
+		return 0, err
+	}
+	return len(tenants), nil
+}
+
 func (s *Raft) DeleteTenants(class string, req *cmd.DeleteTenantsRequest) (uint64, error) {
 	if class == "" || req == nil {
 		return 0, fmt.Errorf("empty class name or nil request : %w", schema.ErrBadRequest)
@@ -185,6 +272,483 @@ func (s *Raft) UpdateTenantsProcess(class string, req *cmd.TenantProcessRequest)
 	return s.Execute(command)
 }
 
+func (s *Raft) UpdateTenantTombstoneTTL(class string, ttl time.Duration) (uint64, error) {
+	if class == "" {
+		return 0, fmt.Errorf("empty class name : %w", schema.ErrBadRequest)
+	}
+	req := cmd.UpdateTenantTombstoneTTLRequest{TTL: ttl}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_UPDATE_TENANT_TOMBSTONE_TTL,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+// UpdateTenantReplicationFactor overrides the replication factor of tenant, which is validated against nodes.
+// A factor of 0 removes the override.
+func (s *Raft) UpdateTenantReplicationFactor(class, tenant string, factor int64, nodes []string) (uint64, error) {
+	if class == "" || tenant == "" || factor < 0 {
+		return 0, fmt.Errorf("empty class or tenant name or negative factor : %w", schema.ErrBadRequest)
+	}
+	if factor > int64(len(nodes)) {
+		return 0, fmt.Errorf("not enough nodes for replication factor: found %d want %d: %w", len(nodes), factor, schema.ErrBadRequest)
+	}
+	req := cmd.UpdateTenantReplicationFactorRequest{Tenant: tenant, Factor: factor, ClusterNodes: nodes}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_UPDATE_TENANT_REPLICATION_FACTOR,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+func (s *Raft) SuspendTenantCreation(class string, suspend bool) (uint64, error) {
+	if class == "" {
+		return 0, fmt.Errorf("empty class name : %w", schema.ErrBadRequest)
+	}
+	req := cmd.SuspendTenantCreationRequest{Suspend: suspend}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_SUSPEND_TENANT_CREATION,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+func (s *Raft) SetNodeTenantsStatus(class, node, status string) (uint64, error) {
+	if class == "" || node == "" || status == "" {
+		return 0, fmt.Errorf("empty class name, node or status : %w", schema.ErrBadRequest)
+	}
+	req := cmd.SetNodeTenantsStatusRequest{Node: node, Status: status}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_SET_NODE_TENANTS_STATUS,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+func (s *Raft) SetTenantNodes(class, tenant string, nodes []string) (uint64, error) {
+	if class == "" || tenant == "" || len(nodes) == 0 {
+		return 0, fmt.Errorf("empty class or tenant name or empty node list : %w", schema.ErrBadRequest)
+	}
+	req := cmd.SetTenantNodesRequest{Tenant: tenant, Nodes: nodes}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_SET_TENANT_NODES,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+func (s *Raft) MergeTenantMetadata(class string, updates map[string]map[string]string) (uint64, error) {
+	if class == "" || len(updates) == 0 {
+		return 0, fmt.Errorf("empty class name or no updates : %w", schema.ErrBadRequest)
+	}
+	req := cmd.MergeTenantMetadataRequest{Updates: updates}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_MERGE_TENANT_METADATA,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+// DedupeShardOwners removes duplicate nodes from the node list of every shard of class.
+// It returns the sorted names of the repaired shards.
+func (s *Raft) DedupeShardOwners(class string) (fixed []string, err error) {
+	if class == "" {
+		return nil, fmt.Errorf("empty class name : %w", schema.ErrBadRequest)
+	}
+	fixed, err = s.SchemaReader().DuplicateShardOwners(class)
+	if err != nil || len(fixed) == 0 {
+		return nil, err
+	}
+	req := cmd.DedupeShardOwnersRequest{Shards: fixed}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_DEDUPE_SHARD_OWNERS,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	if _, err := s.Execute(command); err != nil {
+		return nil, err
+	}
+	return fixed, nil
+}
+
+// RepairBlankPrimaries promotes the first non-blank node of every shard of class with a blank primary.
+// It returns the sorted names of the repaired shards. Shards without any non-blank node are reported
+// in an error wrapping ErrShardOwnerNotFound, while the repairable shards are still fixed.
+func (s *Raft) RepairBlankPrimaries(class string) (fixed []string, err error) {
+	if class == "" {
+		return nil, fmt.Errorf("empty class name : %w", schema.ErrBadRequest)
+	}
+	fixed, unrepairable, err := s.SchemaReader().BlankPrimaries(class)
+	if err != nil {
+		return nil, err
+	}
+	var ownerErr error
+	if len(unrepairable) > 0 {
+		ownerErr = fmt.Errorf("%w: shards %v", schema.ErrShardOwnerNotFound, unrepairable)
+	}
+	if len(fixed) == 0 {
+		return nil, ownerErr
+	}
+	req := cmd.RepairBlankPrimariesRequest{Shards: fixed}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_REPAIR_BLANK_PRIMARIES,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	if _, err := s.Execute(command); err != nil {
+		return nil, err
+	}
+	return fixed, ownerErr
+}
+
+// NormalizeEmptyStatuses sets the status of every tenant of class with an empty status to defaultStatus.
+// It returns the sorted names of the repaired tenants.
+func (s *Raft) NormalizeEmptyStatuses(class, defaultStatus string) (fixed []string, err error) {
+	if class == "" || defaultStatus == "" {
+		return nil, fmt.Errorf("empty class name or default status : %w", schema.ErrBadRequest)
+	}
+	fixed, err = s.SchemaReader().EmptyStatusTenants(class)
+	if err != nil || len(fixed) == 0 {
+		return nil, err
+	}
+	req := cmd.NormalizeEmptyStatusesRequest{Tenants: fixed, DefaultStatus: defaultStatus}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_NORMALIZE_EMPTY_STATUSES,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	if _, err := s.Execute(command); err != nil {
+		return nil, err
+	}
+	return fixed, nil
+}
+
+// RemapNodes replaces the nodes of the shards of class which are keys of mapping by their mapped node.
+// It returns the sorted names of the changed shards.
+func (s *Raft) RemapNodes(class string, mapping map[string]string) (changed []string, err error) {
+	if class == "" || len(mapping) == 0 {
+		return nil, fmt.Errorf("empty class name or mapping : %w", schema.ErrBadRequest)
+	}
+	changed, err = s.SchemaReader().RemapNodesPlan(class, mapping)
+	if err != nil || len(changed) == 0 {
+		return nil, err
+	}
+	req := cmd.RemapNodesRequest{Mapping: mapping}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_REMAP_NODES,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	if _, err := s.Execute(command); err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+func (s *Raft) ResetTenant(class, tenant, status string, nodes []string) (uint64, error) {
+	if class == "" || tenant == "" || len(nodes) == 0 {
+		return 0, fmt.Errorf("empty class or tenant name or empty node list : %w", schema.ErrBadRequest)
+	}
+	req := cmd.ResetTenantRequest{Tenant: tenant, Status: status, Nodes: nodes}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_RESET_TENANT,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+// RebalanceClass spreads the primary ownership of the shards of class evenly across nodes.
+// It returns the applied plan. An error wrapping schema.ErrRebalanceConflict is returned if the
+// state changed between planning and applying; callers may retry.
+func (s *Raft) RebalanceClass(class string, nodes []string) ([]schema.ShardMove, error) {
+	if class == "" || len(nodes) == 0 {
+		return nil, fmt.Errorf("empty class name or empty node list : %w", schema.ErrBadRequest)
+	}
+	plan, err := s.SchemaReader().ComputeRebalancePlan(class, nodes)
+	if err != nil || len(plan) == 0 {
+		return nil, err
+	}
+	req := cmd.ApplyRebalancePlanRequest{Moves: make([]cmd.ShardMove, len(plan))}
+	for i, mv := range plan {
+		req.Moves[i] = cmd.ShardMove{Shard: mv.Shard, From: mv.From, To: mv.To}
+	}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_APPLY_REBALANCE_PLAN,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	if _, err := s.Execute(command); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *Raft) AddShard(class, shard string, nodes []string, status string) (uint64, error) {
+	if class == "" || shard == "" || len(nodes) == 0 {
+		return 0, fmt.Errorf("empty class or shard name or empty node list : %w", schema.ErrBadRequest)
+	}
+	req := cmd.AddShardRequest{Shard: shard, Nodes: nodes, Status: status}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_ADD_SHARD,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+func (s *Raft) RemoveShard(class, shard string) (uint64, error) {
+	if class == "" || shard == "" {
+		return 0, fmt.Errorf("empty class or shard name : %w", schema.ErrBadRequest)
+	}
+	req := cmd.RemoveShardRequest{Shard: shard}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_REMOVE_SHARD,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+// HealReplicationBalanced adds replicas to every shard of class with less replicas than its replication
+// factor, choosing the least loaded nodes of nodeLoads. It returns the sorted names of the changed shards
+// and the updated loads.
+func (s *Raft) HealReplicationBalanced(class string, nodeLoads map[string]int) (changed []string, newLoads map[string]int, err error) {
+	if class == "" {
+		return nil, nil, fmt.Errorf("empty class name : %w", schema.ErrBadRequest)
+	}
+	plan, newLoads, err := s.SchemaReader().HealReplicationBalanced(class, nodeLoads)
+	if err != nil {
+		return nil, nil, err
+	}
+	changed, err = s.addReplicas(class, plan)
+	if err != nil {
+		return nil, nil, err
+	}
+	return changed, newLoads, nil
+}
+
+// RaiseFactorAndHeal raises the replication factor of class to newFactor through UpdateClass and adds
+// the missing replicas of all shards, choosing the least loaded of candidateNodes. Nothing is submitted
+// if any shard can't reach its factor with the candidates. It returns the sorted names of the changed shards.
+func (s *Raft) RaiseFactorAndHeal(class string, newFactor int, candidateNodes []string, nodeLoads map[string]int) (changed []string, err error) {
+	if class == "" {
+		return nil, fmt.Errorf("empty class name : %w", schema.ErrBadRequest)
+	}
+	plan, err := s.SchemaReader().RaiseFactorPlan(class, newFactor, candidateNodes, nodeLoads)
+	if err != nil {
+		return nil, err
+	}
+	cls := s.SchemaReader().ReadOnlyClass(class)
+	if cls == nil {
+		return nil, schema.ErrClassNotFound
+	}
+
+	updated := *cls
+	rc := models.ReplicationConfig{}
+	if cls.ReplicationConfig != nil {
+		rc = *cls.ReplicationConfig
+	}
+	rc.Factor = int64(newFactor)
+	updated.ReplicationConfig = &rc
+	if _, err := s.UpdateClass(&updated, nil); err != nil {
+		return nil, fmt.Errorf("update replication factor: %w", err)
+	}
+	return s.addReplicas(class, plan)
+}
+
+// addReplicas submits the replicas of plan and returns the sorted names of their shards
+func (s *Raft) addReplicas(class string, plan map[string][]string) ([]string, error) {
+	if len(plan) == 0 {
+		return []string{}, nil
+	}
+	req := cmd.AddReplicasRequest{Replicas: plan}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_ADD_REPLICAS,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	if _, err := s.Execute(command); err != nil {
+		return nil, err
+	}
+	changed := make([]string, 0, len(plan))
+	for name := range plan {
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// EnsureTenant creates tenant of class with status and nodes if it doesn't exist and otherwise
+// updates its status if it differs. It returns TenantAdded or TenantUpdated, or an empty type
+// if nothing changed.
+func (s *Raft) EnsureTenant(class, tenant, status string, nodes []string) (schema.TenantChangeType, error) {
+	if class == "" || tenant == "" {
+		return "", fmt.Errorf("empty class or tenant name : %w", schema.ErrBadRequest)
+	}
+	ops, err := s.SchemaReader().EnsureTenantBatch(class, tenant, status, nodes)
+	if err != nil || len(ops.Creates)+len(ops.Updates) == 0 {
+		return "", err
+	}
+	if _, err := s.ApplyTenantBatch(class, ops); err != nil {
+		return "", err
+	}
+	if len(ops.Creates) > 0 {
+		return schema.TenantAdded, nil
+	}
+	return schema.TenantUpdated, nil
+}
+
+func (s *Raft) ApplyTenantBatch(class string, ops schema.TenantBatch) (uint64, error) {
+	if class == "" || len(ops.Creates)+len(ops.Deletes)+len(ops.Updates) == 0 {
+		return 0, fmt.Errorf("empty class name or empty batch : %w", schema.ErrBadRequest)
+	}
+	req := cmd.ApplyTenantBatchRequest{
+		Creates:            make([]cmd.TenantSpec, len(ops.Creates)),
+		Deletes:            ops.Deletes,
+		Updates:            ops.Updates,
+		SkipPlacementCheck: ops.SkipPlacementCheck,
+	}
+	for i, spec := range ops.Creates {
+		req.Creates[i] = cmd.TenantSpec{Name: spec.Name, Status: spec.Status, Nodes: spec.Nodes}
+	}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_APPLY_TENANT_BATCH,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+// DedupeProperties removes every property of class whose name, compared case-insensitively,
+// was already used by a previous property. It returns the names of the removed properties in class order.
+func (s *Raft) DedupeProperties(class string) (removed []string, err error) {
+	if class == "" {
+		return nil, fmt.Errorf("empty class name : %w", schema.ErrBadRequest)
+	}
+	removed, err = s.SchemaReader().DuplicateProperties(class)
+	if err != nil || len(removed) == 0 {
+		return nil, err
+	}
+	subCommand, err := json.Marshal(&cmd.DedupePropertiesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_DEDUPE_PROPERTIES,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	if _, err := s.Execute(command); err != nil {
+		return nil, err
+	}
+	return removed, nil
+}
+
+func (s *Raft) SetMaxPropertyCount(class string, limit int) (uint64, error) {
+	if class == "" || limit < 0 {
+		return 0, fmt.Errorf("empty class name or negative limit : %w", schema.ErrBadRequest)
+	}
+	req := cmd.SetMaxPropertyCountRequest{Limit: limit}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_SET_MAX_PROPERTY_COUNT,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
+func (s *Raft) SetMaxTenantDeletes(class string, limit int) (uint64, error) {
+	if class == "" || limit < 0 {
+		return 0, fmt.Errorf("empty class name or negative limit : %w", schema.ErrBadRequest)
+	}
+	req := cmd.SetMaxTenantDeletesRequest{Limit: limit}
+	subCommand, err := json.Marshal(&req)
+	if err != nil {
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+	command := &cmd.ApplyRequest{
+		Type:       cmd.ApplyRequest_TYPE_SET_MAX_TENANT_DELETES,
+		Class:      class,
+		SubCommand: subCommand,
+	}
+	return s.Execute(command)
+}
+
 func (s *Raft) StoreSchemaV1() error {
 	command := &cmd.ApplyRequest{
 		Type: cmd.ApplyRequest_TYPE_STORE_SCHEMA_V1,
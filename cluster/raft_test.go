@@ -227,7 +227,7 @@ func TestRaftEndpoints(t *testing.T) {
 	assert.ErrorIs(t, err, schema.ErrBadRequest)
 	version, err = srv.AddTenants("C", &command.AddTenantsRequest{
 		ClusterNodes: []string{"Node-1"},
-		Tenants:      []*command.Tenant{nil, {Name: "T2", Status: "S1"}, nil},
+		Tenants:      []*command.Tenant{nil, {Name: "T2", Status: models.TenantActivityStatusHOT}, nil},
 	})
 	assert.Nil(t, err)
 	info.ShardVersion = version
@@ -237,7 +237,17 @@ func TestRaftEndpoints(t *testing.T) {
 	// UpdateTenants
 	_, err = srv.UpdateTenants("", &command.UpdateTenantsRequest{})
 	assert.ErrorIs(t, err, schema.ErrBadRequest)
-	_, err = srv.UpdateTenants("C", &command.UpdateTenantsRequest{Tenants: []*command.Tenant{{Name: "T2", Status: "S2"}}})
+	_, err = srv.UpdateTenants("C", &command.UpdateTenantsRequest{Tenants: []*command.Tenant{{Name: "T2", Status: models.TenantActivityStatusCOLD}}})
+	assert.Nil(t, err)
+
+	// RestoreTenantStatuses
+	snapshot, err := schemaReader.SnapshotStatuses("C")
+	assert.Nil(t, err)
+	_, err = srv.UpdateTenants("C", &command.UpdateTenantsRequest{Tenants: []*command.Tenant{{Name: "T2", Status: models.TenantActivityStatusHOT}}})
+	assert.Nil(t, err)
+	_, err = srv.RestoreTenantStatuses("C", map[string]string{"T2": "WARM"})
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	_, err = srv.RestoreTenantStatuses("C", snapshot)
 	assert.Nil(t, err)
 
 	// DeleteTenants
@@ -248,7 +258,123 @@ func TestRaftEndpoints(t *testing.T) {
 	info.Tenants -= 1
 	info.ShardVersion = version
 	assert.Equal(t, info, schemaReader.ClassInfo("C"))
-	assert.Equal(t, "S2", schemaReader.CopyShardingState("C").Physical["T2"].Status)
+	assert.Equal(t, models.TenantActivityStatusCOLD, schemaReader.CopyShardingState("C").Physical["T2"].Status)
+
+	// FreezeTenants
+	_, _, err = srv.FreezeTenants("", []string{"T2"})
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	frozen, missing, err := srv.FreezeTenants("C", []string{"T2", "X"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, frozen)
+	assert.Equal(t, []string{"X"}, missing)
+	assert.Equal(t, types.TenantActivityStatusFREEZING, schemaReader.CopyShardingState("C").Physical["T2"].Status)
+	info.ShardVersion = schemaReader.ClassInfo("C").ShardVersion
+	_, err = srv.FreezeTenantsOnNode("C", "")
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	frozen, err = srv.FreezeTenantsOnNode("C", "Node-1")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, frozen, "T2 is being frozen already")
+	_, err = srv.FreezeTenantsByLabel("C", "", "")
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	frozen, err = srv.FreezeTenantsByLabel("C", "tier", "free")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, frozen)
+
+	// DedupeShardOwners
+	_, err = srv.DedupeShardOwners("")
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	fixed, err := srv.DedupeShardOwners("C")
+	assert.Nil(t, err)
+	assert.Empty(t, fixed)
+
+	// RepairBlankPrimaries
+	_, err = srv.RepairBlankPrimaries("")
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	fixed, err = srv.RepairBlankPrimaries("C")
+	assert.Nil(t, err)
+	assert.Empty(t, fixed)
+
+	// NormalizeEmptyStatuses
+	_, err = srv.NormalizeEmptyStatuses("C", "")
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	fixed, err = srv.NormalizeEmptyStatuses("C", models.TenantActivityStatusHOT)
+	assert.Nil(t, err)
+	assert.Empty(t, fixed)
+
+	// RemapNodes
+	_, err = srv.RemapNodes("C", nil)
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	changed, err := srv.RemapNodes("C", map[string]string{"Node-1": "Node-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"T2"}, changed)
+	assert.Equal(t, []string{"Node-2"}, srv.SchemaReader().CopyShardingState("C").Physical["T2"].BelongsToNodes)
+	info = srv.SchemaReader().ClassInfo("C")
+
+	// ResetTenant
+	_, err = srv.ResetTenant("C", "T2", models.TenantActivityStatusHOT, nil)
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	_, err = srv.ResetTenant("C", "T3", models.TenantActivityStatusHOT, []string{"Node-1"})
+	assert.ErrorIs(t, err, schema.ErrShardNotFound)
+
+	// RebalanceClass
+	_, err = srv.RebalanceClass("C", nil)
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	plan, err := srv.RebalanceClass("C", []string{"Node-1"})
+	assert.Nil(t, err)
+	assert.Equal(t, []schema.ShardMove{{Shard: "T2", From: "Node-2", To: "Node-1"}}, plan)
+	assert.Equal(t, []string{"Node-1"}, srv.SchemaReader().CopyShardingState("C").Physical["T2"].BelongsToNodes)
+	info = srv.SchemaReader().ClassInfo("C")
+
+	// AddShard
+	_, err = srv.AddShard("C", "S1", nil, models.TenantActivityStatusHOT)
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+
+	// RemoveShard
+	_, err = srv.RemoveShard("C", "")
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+
+	// HealReplicationBalanced
+	_, _, err = srv.HealReplicationBalanced("", nil)
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	changed, _, err = srv.HealReplicationBalanced("C", map[string]int{"Node-2": 0})
+	assert.Nil(t, err)
+	assert.Empty(t, changed)
+
+	// RaiseFactorAndHeal
+	_, err = srv.RaiseFactorAndHeal("C", 3, []string{"Node-1"}, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, info, srv.SchemaReader().ClassInfo("C"), "nothing is submitted")
+
+	// ApplyTenantBatch
+	_, err = srv.ApplyTenantBatch("C", schema.TenantBatch{})
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	_, err = srv.ApplyTenantBatch("C", schema.TenantBatch{Deletes: []string{"T3"}})
+	assert.ErrorIs(t, err, schema.ErrShardNotFound)
+
+	// EnsureTenant
+	typ, err := srv.EnsureTenant("C", "T2", models.TenantActivityStatusFREEZING, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, typ, "nothing to change")
+	_, err = srv.EnsureTenant("C", "", models.TenantActivityStatusHOT, nil)
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+
+	// DedupeProperties
+	removed, err := srv.DedupeProperties("C")
+	assert.Nil(t, err)
+	assert.Empty(t, removed)
+
+	// SetMaxPropertyCount
+	_, err = srv.SetMaxPropertyCount("C", -1)
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	_, err = srv.SetMaxPropertyCount("C", 0)
+	assert.Nil(t, err)
+	info = srv.SchemaReader().ClassInfo("C")
+
+	// SetMaxTenantDeletes
+	_, err = srv.SetMaxTenantDeletes("C", -1)
+	assert.ErrorIs(t, err, schema.ErrBadRequest)
+	_, err = srv.SetMaxTenantDeletes("C", 0)
+	assert.Nil(t, err)
 
 	// Self Join
 	assert.Nil(t, srv.Join(ctx, m.store.cfg.NodeID, addr, true))
@@ -17,6 +17,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/hashicorp/raft"
 	"github.com/sirupsen/logrus"
@@ -129,7 +130,8 @@ func (s *SchemaManager) Close(ctx context.Context) (err error) {
 	return s.db.Close(ctx)
 }
 
-func (s *SchemaManager) AddClass(cmd *command.ApplyRequest, nodeID string, schemaOnly bool) error {
+// AddClass adds the class of cmd. createdAt is the time the command was appended to the log.
+func (s *SchemaManager) AddClass(cmd *command.ApplyRequest, nodeID string, schemaOnly bool, createdAt time.Time) error {
 	req := command.AddClassRequest{}
 	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
 		return fmt.Errorf("%w: %w", ErrBadRequest, err)
@@ -144,7 +146,7 @@ func (s *SchemaManager) AddClass(cmd *command.ApplyRequest, nodeID string, schem
 	return s.apply(
 		applyOp{
 			op:                    cmd.GetType().String(),
-			updateSchema:          func() error { return s.schema.addClass(req.Class, req.State, cmd.Version) },
+			updateSchema:          func() error { return s.schema.addClass(req.Class, req.State, cmd.Version, createdAt) },
 			updateStore:           func() error { return s.db.AddClass(req) },
 			schemaOnly:            schemaOnly,
 			triggerSchemaCallback: true,
@@ -152,7 +154,8 @@ func (s *SchemaManager) AddClass(cmd *command.ApplyRequest, nodeID string, schem
 	)
 }
 
-func (s *SchemaManager) RestoreClass(cmd *command.ApplyRequest, nodeID string, schemaOnly bool) error {
+// RestoreClass restores the class of cmd. createdAt is the time the command was appended to the log.
+func (s *SchemaManager) RestoreClass(cmd *command.ApplyRequest, nodeID string, schemaOnly bool, createdAt time.Time) error {
 	req := command.AddClassRequest{}
 	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
 		return fmt.Errorf("%w: %w", ErrBadRequest, err)
@@ -174,7 +177,7 @@ func (s *SchemaManager) RestoreClass(cmd *command.ApplyRequest, nodeID string, s
 	return s.apply(
 		applyOp{
 			op:                    cmd.GetType().String(),
-			updateSchema:          func() error { return s.schema.addClass(req.Class, req.State, cmd.Version) },
+			updateSchema:          func() error { return s.schema.addClass(req.Class, req.State, cmd.Version, createdAt) },
 			updateStore:           func() error { return s.db.AddClass(req) },
 			schemaOnly:            schemaOnly,
 			triggerSchemaCallback: true,
@@ -284,7 +287,8 @@ func (s *SchemaManager) UpdateShardStatus(cmd *command.ApplyRequest, schemaOnly
 	)
 }
 
-func (s *SchemaManager) AddTenants(cmd *command.ApplyRequest, schemaOnly bool) error {
+// AddTenants adds the tenants of cmd. modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) AddTenants(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
 	req := &command.AddTenantsRequest{}
 	if err := gproto.Unmarshal(cmd.SubCommand, req); err != nil {
 		return fmt.Errorf("%w: %w", ErrBadRequest, err)
@@ -293,14 +297,15 @@ func (s *SchemaManager) AddTenants(cmd *command.ApplyRequest, schemaOnly bool) e
 	return s.apply(
 		applyOp{
 			op:           cmd.GetType().String(),
-			updateSchema: func() error { return s.schema.addTenants(cmd.Class, cmd.Version, req) },
+			updateSchema: func() error { return s.schema.addTenants(cmd.Class, cmd.Version, req, modifiedAt) },
 			updateStore:  func() error { return s.db.AddTenants(cmd.Class, req) },
 			schemaOnly:   schemaOnly,
 		},
 	)
 }
 
-func (s *SchemaManager) UpdateTenants(cmd *command.ApplyRequest, schemaOnly bool) error {
+// UpdateTenants updates the tenants of cmd. modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) UpdateTenants(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
 	req := &command.UpdateTenantsRequest{}
 	if err := gproto.Unmarshal(cmd.SubCommand, req); err != nil {
 		return fmt.Errorf("%w: %w", ErrBadRequest, err)
@@ -312,14 +317,15 @@ func (s *SchemaManager) UpdateTenants(cmd *command.ApplyRequest, schemaOnly bool
 			// updateSchema func will update the request's tenants and therefore we use it as a filter that is then sent
 			// to the updateStore function. This allows us to effectively use the schema update to narrow down work for
 			// the DB update.
-			updateSchema: func() error { return s.schema.updateTenants(cmd.Class, cmd.Version, req) },
+			updateSchema: func() error { return s.schema.updateTenants(cmd.Class, cmd.Version, req, modifiedAt) },
 			updateStore:  func() error { return s.db.UpdateTenants(cmd.Class, req) },
 			schemaOnly:   schemaOnly,
 		},
 	)
 }
 
-func (s *SchemaManager) DeleteTenants(cmd *command.ApplyRequest, schemaOnly bool) error {
+// DeleteTenants deletes the tenants of cmd. modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) DeleteTenants(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
 	req := &command.DeleteTenantsRequest{}
 	if err := gproto.Unmarshal(cmd.SubCommand, req); err != nil {
 		return fmt.Errorf("%w: %w", ErrBadRequest, err)
@@ -328,14 +334,15 @@ func (s *SchemaManager) DeleteTenants(cmd *command.ApplyRequest, schemaOnly bool
 	return s.apply(
 		applyOp{
 			op:           cmd.GetType().String(),
-			updateSchema: func() error { return s.schema.deleteTenants(cmd.Class, cmd.Version, req) },
+			updateSchema: func() error { return s.schema.deleteTenants(cmd.Class, cmd.Version, req, modifiedAt) },
 			updateStore:  func() error { return s.db.DeleteTenants(cmd.Class, req) },
 			schemaOnly:   schemaOnly,
 		},
 	)
 }
 
-func (s *SchemaManager) UpdateTenantsProcess(cmd *command.ApplyRequest, schemaOnly bool) error {
+// UpdateTenantsProcess applies the tenant process of cmd. modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) UpdateTenantsProcess(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
 	req := &command.TenantProcessRequest{}
 	if err := gproto.Unmarshal(cmd.SubCommand, req); err != nil {
 		return fmt.Errorf("%w: %w", ErrBadRequest, err)
@@ -344,13 +351,384 @@ func (s *SchemaManager) UpdateTenantsProcess(cmd *command.ApplyRequest, schemaOn
 	return s.apply(
 		applyOp{
 			op:           cmd.GetType().String(),
-			updateSchema: func() error { return s.schema.updateTenantsProcess(cmd.Class, cmd.Version, req) },
+			updateSchema: func() error { return s.schema.updateTenantsProcess(cmd.Class, cmd.Version, req, modifiedAt) },
 			updateStore:  func() error { return s.db.UpdateTenantsProcess(cmd.Class, req) },
 			schemaOnly:   schemaOnly,
 		},
 	)
 }
 
+// SetNodeTenantsStatus sets the status of the tenants whose primary owner is the node of cmd.
+// modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) SetNodeTenantsStatus(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.SetNodeTenantsStatusRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	// the schema update selects the tenants of the node and returns the ones the DB has to update
+	var dbReq *command.UpdateTenantsRequest
+	return s.apply(
+		applyOp{
+			op: cmd.GetType().String(),
+			updateSchema: func() (err error) {
+				dbReq, err = s.schema.setStatusForNodeTenants(cmd.Class, cmd.Version, &req, modifiedAt)
+				return err
+			},
+			updateStore: func() error { return s.db.UpdateTenants(cmd.Class, dbReq) },
+			schemaOnly:  schemaOnly,
+		},
+	)
+}
+
+// UpdateTenantTombstoneTTL sets how long deleted tenants of the class of cmd can't be created again
+func (s *SchemaManager) UpdateTenantTombstoneTTL(cmd *command.ApplyRequest, schemaOnly bool) error {
+	req := command.UpdateTenantTombstoneTTLRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.updateTenantTombstoneTTL(cmd.Class, req.TTL) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// UpdateTenantReplicationFactor overrides the class replication factor for the tenant of cmd
+func (s *SchemaManager) UpdateTenantReplicationFactor(cmd *command.ApplyRequest, schemaOnly bool) error {
+	req := command.UpdateTenantReplicationFactorRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.updateTenantReplicationFactor(cmd.Class, cmd.Version, &req) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// SuspendTenantCreation blocks or unblocks the creation of new tenants of the class of cmd
+func (s *SchemaManager) SuspendTenantCreation(cmd *command.ApplyRequest, schemaOnly bool) error {
+	req := command.SuspendTenantCreationRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.suspendTenantCreation(cmd.Class, req.Suspend) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// SetTenantNodes replaces the nodes of the tenant of cmd. modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) SetTenantNodes(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.SetTenantNodesRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.setTenantNodes(cmd.Class, cmd.Version, &req, modifiedAt) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// MergeTenantMetadata merges the labels of cmd into the metadata of their tenants.
+// modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) MergeTenantMetadata(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.MergeTenantMetadataRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.mergeTenantMetadata(cmd.Class, cmd.Version, &req, modifiedAt) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// DedupeShardOwners removes duplicate nodes from the shards of cmd. modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) DedupeShardOwners(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.DedupeShardOwnersRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.dedupeShardOwners(cmd.Class, cmd.Version, &req, modifiedAt) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// RepairBlankPrimaries repairs the blank primaries of the shards of cmd. modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) RepairBlankPrimaries(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.RepairBlankPrimariesRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.repairBlankPrimaries(cmd.Class, cmd.Version, &req, modifiedAt) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// NormalizeEmptyStatuses sets the default status of cmd on its tenants with an empty status.
+// modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) NormalizeEmptyStatuses(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.NormalizeEmptyStatusesRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	// the schema update returns the tenants whose effective status changed for the DB to update
+	var dbReq *command.UpdateTenantsRequest
+	return s.apply(
+		applyOp{
+			op: cmd.GetType().String(),
+			updateSchema: func() (err error) {
+				dbReq, err = s.schema.normalizeEmptyStatuses(cmd.Class, cmd.Version, &req, modifiedAt)
+				return err
+			},
+			updateStore: func() error { return s.db.UpdateTenants(cmd.Class, dbReq) },
+			schemaOnly:  schemaOnly,
+		},
+	)
+}
+
+// RemapNodes replaces the nodes of the shards of cmd according to its mapping.
+// modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) RemapNodes(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.RemapNodesRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.remapNodes(cmd.Class, cmd.Version, &req, modifiedAt) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// ResetTenant replaces the shard of the tenant of cmd by a fresh shard.
+// modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) ResetTenant(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.ResetTenantRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.resetTenant(cmd.Class, cmd.Version, &req, modifiedAt) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// ApplyRebalancePlan applies the moves of cmd to the primary ownership of its shards.
+// modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) ApplyRebalancePlan(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.ApplyRebalancePlanRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.applyRebalancePlan(cmd.Class, cmd.Version, &req, modifiedAt) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// AddShard adds the shard of cmd to its class. modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) AddShard(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.AddShardRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	// the schema update returns the shard to be created if it belongs to this node
+	var dbReq *command.AddTenantsRequest
+	return s.apply(
+		applyOp{
+			op: cmd.GetType().String(),
+			updateSchema: func() (err error) {
+				dbReq, err = s.schema.addShard(cmd.Class, cmd.Version, &req, modifiedAt)
+				return err
+			},
+			updateStore: func() error { return s.db.AddTenants(cmd.Class, dbReq) },
+			schemaOnly:  schemaOnly,
+		},
+	)
+}
+
+// RemoveShard removes the shard of cmd from its class. modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) RemoveShard(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.RemoveShardRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.removeShard(cmd.Class, cmd.Version, &req, modifiedAt) },
+			updateStore: func() error {
+				return s.db.DeleteTenants(cmd.Class, &command.DeleteTenantsRequest{Tenants: []string{req.Shard}})
+			},
+			schemaOnly: schemaOnly,
+		},
+	)
+}
+
+// AddReplicas adds the replicas of cmd to their shards. modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) AddReplicas(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.AddReplicasRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	// the schema update returns the shards which got a replica on this node
+	var dbReq *command.AddTenantsRequest
+	return s.apply(
+		applyOp{
+			op: cmd.GetType().String(),
+			updateSchema: func() (err error) {
+				dbReq, err = s.schema.addReplicas(cmd.Class, cmd.Version, &req, modifiedAt)
+				return err
+			},
+			updateStore: func() error { return s.db.AddTenants(cmd.Class, dbReq) },
+			schemaOnly:  schemaOnly,
+		},
+	)
+}
+
+// ApplyTenantBatch applies the tenant operations of cmd at once.
+// modifiedAt is the time the command was appended to the log.
+func (s *SchemaManager) ApplyTenantBatch(cmd *command.ApplyRequest, schemaOnly bool, modifiedAt time.Time) error {
+	req := command.ApplyTenantBatchRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+	ops := TenantBatch{Deletes: req.Deletes, Updates: req.Updates, SkipPlacementCheck: req.SkipPlacementCheck}
+	for _, spec := range req.Creates {
+		ops.Creates = append(ops.Creates, TenantSpec{Name: spec.Name, Status: spec.Status, Nodes: spec.Nodes})
+	}
+
+	// the schema update reports which operations the DB of this node has to apply
+	var res TenantBatchResult
+	return s.apply(
+		applyOp{
+			op: cmd.GetType().String(),
+			updateSchema: func() (err error) {
+				res, err = s.schema.applyTenantBatch(cmd.Class, cmd.Version, ops, modifiedAt)
+				return err
+			},
+			updateStore: func() error {
+				adds, deletes, updates := res.localRequests(ops)
+				if err := s.db.AddTenants(cmd.Class, adds); err != nil {
+					return err
+				}
+				if err := s.db.DeleteTenants(cmd.Class, deletes); err != nil {
+					return err
+				}
+				return s.db.UpdateTenants(cmd.Class, updates)
+			},
+			schemaOnly: schemaOnly,
+		},
+	)
+}
+
+// DedupeProperties removes the duplicate properties of the class of cmd
+func (s *SchemaManager) DedupeProperties(cmd *command.ApplyRequest, schemaOnly bool) error {
+	req := command.DedupePropertiesRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:                    cmd.GetType().String(),
+			updateSchema:          func() error { return s.schema.dedupeProperties(cmd.Class, cmd.Version) },
+			updateStore:           func() error { return nil },
+			schemaOnly:            schemaOnly,
+			triggerSchemaCallback: true,
+		},
+	)
+}
+
+// SetMaxPropertyCount sets the maximum number of properties of the class of cmd
+func (s *SchemaManager) SetMaxPropertyCount(cmd *command.ApplyRequest, schemaOnly bool) error {
+	req := command.SetMaxPropertyCountRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.setMaxPropertyCount(cmd.Class, cmd.Version, req.Limit) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
+// SetMaxTenantDeletes sets the maximum number of tenants deleted by a single request for the class of cmd
+func (s *SchemaManager) SetMaxTenantDeletes(cmd *command.ApplyRequest, schemaOnly bool) error {
+	req := command.SetMaxTenantDeletesRequest{}
+	if err := json.Unmarshal(cmd.SubCommand, &req); err != nil {
+		return fmt.Errorf("%w: %w", ErrBadRequest, err)
+	}
+
+	return s.apply(
+		applyOp{
+			op:           cmd.GetType().String(),
+			updateSchema: func() error { return s.schema.setMaxTenantDeletes(cmd.Class, req.Limit) },
+			updateStore:  func() error { return nil },
+			schemaOnly:   schemaOnly,
+		},
+	)
+}
+
 type applyOp struct {
 	op                    string
 	updateSchema          func() error
@@ -379,8 +757,10 @@ func (s *SchemaManager) apply(op applyOp) error {
 	}
 
 	// schema applied 1st to make sure any validation happen before applying it to db
-	if err := op.updateSchema(); err != nil {
-		return fmt.Errorf("%w: %s: %w", ErrSchema, op.op, err)
+	// A partially applied tenant update still has to reach the db for the tenants which were applied
+	schemaErr := op.updateSchema()
+	if schemaErr != nil && !errors.As(schemaErr, &TenantsError{}) {
+		return fmt.Errorf("%w: %s: %w", ErrSchema, op.op, schemaErr)
 	}
 
 	if !op.schemaOnly {
@@ -393,6 +773,9 @@ func (s *SchemaManager) apply(op applyOp) error {
 	if op.triggerSchemaCallback {
 		s.db.TriggerSchemaUpdateCallbacks()
 	}
+	if schemaErr != nil {
+		return fmt.Errorf("%w: %s: %w", ErrSchema, op.op, schemaErr)
+	}
 	return nil
 }
 
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -46,7 +47,7 @@ func TestVersionedSchemaReaderShardReplicas(t *testing.T) {
 	// shard not found
 	ss := &sharding.State{Physical: make(map[string]sharding.Physical)}
 
-	sc.addClass(&models.Class{Class: "C"}, ss, 1)
+	sc.addClass(&models.Class{Class: "C"}, ss, 1, time.Time{})
 
 	_, err = vsc.ShardReplicas(ctx, "C", "S", 1)
 	assert.ErrorIs(t, err, ErrShardNotFound)
@@ -102,7 +103,7 @@ func TestVersionedSchemaReaderClass(t *testing.T) {
 		"S2": {Status: "A", BelongsToNodes: nodes},
 	}}
 
-	assert.Nil(t, sc.schema.addClass(cls1, ss1, 1))
+	assert.Nil(t, sc.schema.addClass(cls1, ss1, 1, time.Time{}))
 	info, err = sc.ClassInfo(ctx, "C", 1)
 	assert.Equal(t, ClassInfo{
 		ReplicationFactor: 1,
@@ -136,7 +137,7 @@ func TestVersionedSchemaReaderClass(t *testing.T) {
 		PartitioningEnabled: true,
 		Physical:            map[string]sharding.Physical{"S1": {Status: "A", BelongsToNodes: nodes}},
 	}
-	sc.schema.addClass(cls2, ss2, 1)
+	sc.schema.addClass(cls2, ss2, 1, time.Time{})
 	cls, err = sc.ReadOnlyClass(ctx, "D", 1)
 	assert.Equal(t, cls, cls2, 1)
 	assert.Nil(t, err)
@@ -179,7 +180,7 @@ func TestSchemaReaderShardReplicas(t *testing.T) {
 	// shard not found
 	ss := &sharding.State{Physical: make(map[string]sharding.Physical)}
 
-	sc.addClass(&models.Class{Class: "C"}, ss, 1)
+	sc.addClass(&models.Class{Class: "C"}, ss, 1, time.Time{})
 
 	_, err = rsc.ShardReplicas("C", "S")
 	assert.ErrorIs(t, err, ErrShardNotFound)
@@ -222,7 +223,7 @@ func TestSchemaReaderClass(t *testing.T) {
 		"S2": {Status: "A", BelongsToNodes: nodes},
 	}}
 
-	sc.schema.addClass(cls1, ss1, 1)
+	sc.schema.addClass(cls1, ss1, 1, time.Time{})
 	assert.Equal(t, sc.ReadOnlyClass("C"), cls1)
 	assert.Equal(t, sc.MultiTenancy("D"), models.MultiTenancyConfig{})
 	assert.Nil(t, sc.Read("C", func(c *models.Class, s *sharding.State) error { return nil }))
@@ -245,7 +246,7 @@ func TestSchemaReaderClass(t *testing.T) {
 		PartitioningEnabled: true,
 		Physical:            map[string]sharding.Physical{"S1": {Status: "A", BelongsToNodes: nodes}},
 	}
-	sc.schema.addClass(cls2, ss2, 1)
+	sc.schema.addClass(cls2, ss2, 1, time.Time{})
 	assert.Equal(t, sc.ReadOnlyClass("D"), cls2)
 	assert.Equal(t, sc.MultiTenancy("D"), models.MultiTenancyConfig{Enabled: true})
 
@@ -278,7 +279,8 @@ func TestSchemaSnapshot(t *testing.T) {
 		}
 	)
 	ss.SetLocalName(node)
-	assert.Nil(t, sc.addClass(cls, ss, 1))
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Nil(t, sc.addClass(cls, ss, 1, createdAt))
 	parser.On("ParseClass", mock.Anything).Return(nil)
 
 	// Create Snapshot
@@ -289,6 +291,7 @@ func TestSchemaSnapshot(t *testing.T) {
 	sc2 := NewSchema("N1", fakes.NewMockSchemaExecutor())
 	assert.Nil(t, sc2.Restore(sink, parser))
 	assert.Equal(t, sc.Classes, sc2.Classes)
+	assert.Equal(t, createdAt, sc2.Classes["C"].CreatedAt())
 
 	// Encoding error
 	sink2 := &MockSnapshotSink{wErr: errAny, rErr: errAny}
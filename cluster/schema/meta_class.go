@@ -12,9 +12,12 @@
 package schema
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/weaviate/weaviate/cluster/proto/api"
 	command "github.com/weaviate/weaviate/cluster/proto/api"
@@ -35,6 +38,28 @@ type (
 		ShardVersion uint64
 		// ShardProcesses map[tenantName-action(FREEZING/UNFREEZING)]map[nodeID]TenantsProcess
 		ShardProcesses map[string]NodeShardProcess
+		// CreationTime is the time the class was created, zero for classes created before it was recorded
+		CreationTime time.Time
+
+		// tenantChanges keeps track of recent tenant mutations for incremental syncs
+		tenantChanges tenantChangeLog
+		// copyStats records the cost of CopyShardingState calls if enabled
+		copyStats copyStats
+		// sequence is incremented on every mutation and by NextSequence
+		sequence atomic.Uint64
+		// Tombstones keeps recently deleted tenants to prevent their resurrection
+		Tombstones tenantTombstones
+		// TenantCreationSuspended blocks the creation of new tenants
+		TenantCreationSuspended bool
+		// PropertyVersions maps lower cased names of properties added through AddProperty
+		// to the raft log index they were added at
+		PropertyVersions map[string]uint64
+		// MaxProperties is the maximum number of properties of the class, 0 means unlimited.
+		// It is part of the replicated state so that every node applies AddProperty alike.
+		MaxProperties int
+		// MaxTenantDeletes is the maximum number of tenants deleted by a single request, 0 means unlimited.
+		// It is part of the replicated state so that every node applies DeleteTenants alike.
+		MaxTenantDeletes int
 	}
 )
 
@@ -65,6 +90,31 @@ func (m *metaClass) ClassInfo() ClassInfo {
 	return ci
 }
 
+// classInfoJSON is the wire format of ClassInfo, decoupled from its field layout
+type classInfoJSON struct {
+	Exists            bool                      `json:"exists"`
+	Properties        int                       `json:"properties"`
+	Tenants           int                       `json:"tenants"`
+	ReplicationFactor int                       `json:"replicationFactor"`
+	MultiTenancy      models.MultiTenancyConfig `json:"multiTenancy"`
+	ClassVersion      uint64                    `json:"classVersion"`
+	ShardVersion      uint64                    `json:"shardVersion"`
+}
+
+// ClassInfoJSON returns the JSON representation of ClassInfo
+func (m *metaClass) ClassInfoJSON() ([]byte, error) {
+	ci := m.ClassInfo()
+	return json.Marshal(classInfoJSON{
+		Exists:            ci.Exists,
+		Properties:        ci.Properties,
+		Tenants:           ci.Tenants,
+		ReplicationFactor: ci.ReplicationFactor,
+		MultiTenancy:      ci.MultiTenancy,
+		ClassVersion:      ci.ClassVersion,
+		ShardVersion:      ci.ShardVersion,
+	})
+}
+
 func (m *metaClass) version() uint64 {
 	if m == nil {
 		return 0
@@ -72,6 +122,13 @@ func (m *metaClass) version() uint64 {
 	return max(m.ClassVersion, m.ShardVersion)
 }
 
+// NextSequence increments and returns the operation sequence of the class.
+// The sequence is also incremented by every mutation so it reflects the total number
+// of operations observed by this class. It doesn't require holding the lock.
+func (m *metaClass) NextSequence() uint64 {
+	return m.sequence.Add(1)
+}
+
 func (m *metaClass) MultiTenancyConfig() (mc models.MultiTenancyConfig, v uint64) {
 	if m == nil {
 		return
@@ -93,21 +150,43 @@ func (m *metaClass) CloneClass() *models.Class {
 	return &cp
 }
 
+// CreatedAt returns the time the class was created.
+// It is the zero time for classes created before the creation time was recorded.
+func (m *metaClass) CreatedAt() time.Time {
+	m.RLock()
+	defer m.RUnlock()
+	return m.CreationTime
+}
+
 // ShardOwner returns the node owner of the specified shard
 func (m *metaClass) ShardOwner(shard string) (string, uint64, error) {
 	m.RLock()
 	defer m.RUnlock()
-	x, ok := m.Sharding.Physical[shard]
+	x, ok := m.Sharding.Physical[m.tenantName(shard)]
 
 	if !ok {
 		return "", 0, ErrShardNotFound
 	}
 	if len(x.BelongsToNodes) < 1 || x.BelongsToNodes[0] == "" {
-		return "", 0, fmt.Errorf("owner node not found")
+		return "", 0, ErrShardOwnerNotFound
 	}
 	return x.BelongsToNodes[0], m.version(), nil
 }
 
+// TenantStatusAndOwner returns the activity status and the owner node of the specified tenant
+func (m *metaClass) TenantStatusAndOwner(tenant string) (status, owner string, err error) {
+	m.RLock()
+	defer m.RUnlock()
+	p, ok := m.Sharding.Physical[m.tenantName(tenant)]
+	if !ok {
+		return "", "", ErrShardNotFound
+	}
+	if len(p.BelongsToNodes) < 1 || p.BelongsToNodes[0] == "" {
+		return "", "", ErrShardOwnerNotFound
+	}
+	return p.ActivityStatus(), p.BelongsToNodes[0], nil
+}
+
 // ShardFromUUID returns shard name of the provided uuid
 func (m *metaClass) ShardFromUUID(uuid []byte) (string, uint64) {
 	m.RLock()
@@ -115,17 +194,51 @@ func (m *metaClass) ShardFromUUID(uuid []byte) (string, uint64) {
 	return m.Sharding.PhysicalShard(uuid), m.version()
 }
 
+// ShardAndStatusFromUUID returns the name and activity status of the shard of the provided uuid.
+// It returns ErrShardNotFound if the uuid can't be resolved to a physical shard.
+// Tenants of multi-tenant classes are resolved by name, so ErrMultiTenancyNotSupported is returned for them.
+func (m *metaClass) ShardAndStatusFromUUID(uuid []byte) (shard, status string, err error) {
+	m.RLock()
+	defer m.RUnlock()
+	if m.Sharding.PartitioningEnabled {
+		return "", "", ErrMultiTenancyNotSupported
+	}
+	if len(m.Sharding.Physical) == 0 || len(m.Sharding.Virtual) == 0 {
+		return "", "", ErrShardNotFound
+	}
+	shard = m.Sharding.PhysicalShard(uuid)
+	p, ok := m.Sharding.Physical[shard]
+	if !ok {
+		return "", "", ErrShardNotFound
+	}
+	return shard, p.ActivityStatus(), nil
+}
+
 // ShardReplicas returns the replica nodes of a shard
 func (m *metaClass) ShardReplicas(shard string) ([]string, uint64, error) {
 	m.RLock()
 	defer m.RUnlock()
-	x, ok := m.Sharding.Physical[shard]
+	x, ok := m.Sharding.Physical[m.tenantName(shard)]
 	if !ok {
 		return nil, 0, ErrShardNotFound
 	}
 	return slices.Clone(x.BelongsToNodes), m.version(), nil
 }
 
+// ShardReplicasExcludingPrimary returns the replica nodes of a shard without its primary owner
+func (m *metaClass) ShardReplicasExcludingPrimary(shard string) ([]string, error) {
+	m.RLock()
+	defer m.RUnlock()
+	x, ok := m.Sharding.Physical[m.tenantName(shard)]
+	if !ok {
+		return nil, ErrShardNotFound
+	}
+	if len(x.BelongsToNodes) < 2 {
+		return []string{}, nil
+	}
+	return slices.Clone(x.BelongsToNodes[1:]), nil
+}
+
 // TenantsShards returns shard name for the provided tenant and its activity status
 func (m *metaClass) TenantsShards(class string, tenants ...string) (map[string]string, uint64) {
 	m.RLock()
@@ -138,7 +251,7 @@ func (m *metaClass) TenantsShards(class string, tenants ...string) (map[string]s
 
 	res := make(map[string]string, len(tenants))
 	for _, t := range tenants {
-		if physical, ok := m.Sharding.Physical[t]; ok {
+		if physical, ok := m.Sharding.Physical[m.tenantName(t)]; ok {
 			res[t] = physical.ActivityStatus()
 		}
 	}
@@ -147,15 +260,44 @@ func (m *metaClass) TenantsShards(class string, tenants ...string) (map[string]s
 
 // CopyShardingState returns a deep copy of the sharding state
 func (m *metaClass) CopyShardingState() (*sharding.State, uint64) {
+	var start time.Time
+	if m.copyStats.enabled.Load() {
+		start = time.Now()
+	}
 	m.RLock()
 	defer m.RUnlock()
 	st := m.Sharding.DeepCopy()
+	if !start.IsZero() {
+		m.copyStats.observe(time.Since(start), approxStateSize(&st))
+	}
 	return &st, m.version()
 }
 
+// Snapshot returns a shallow copy of the class and a deep copy of its sharding state
+// taken under the same lock, so both reflect the same version.
+func (m *metaClass) Snapshot() (*models.Class, *sharding.State) {
+	m.RLock()
+	defer m.RUnlock()
+	cls := m.Class
+	st := m.Sharding.DeepCopy()
+	return &cls, &st
+}
+
 func (m *metaClass) AddProperty(v uint64, props ...*models.Property) error {
+	for _, p := range props {
+		if p == nil {
+			return fmt.Errorf("property is nil")
+		}
+	}
+
 	m.Lock()
 	defer m.Unlock()
+	m.sequence.Add(1)
+
+	if err := m.checkPropertyCount(props); err != nil {
+		return err
+	}
+	m.recordPropertyVersions(v, props)
 
 	// update all at once to prevent race condition with concurrent readers
 	mergedProps := MergeProps(m.Class.Properties, props)
@@ -198,23 +340,83 @@ func MergeProps(old, new []*models.Property) []*models.Property {
 	return mergedProps
 }
 
-func (m *metaClass) AddTenants(nodeID string, req *command.AddTenantsRequest, replFactor int64, v uint64) error {
+// AddTenants adds the requested tenants which don't exist yet with modifiedAt as their last modification time
+func (m *metaClass) AddTenants(nodeID string, req *command.AddTenantsRequest, replFactor int64, v uint64, modifiedAt time.Time) error {
 	req.Tenants = removeNilTenants(req.Tenants)
 	m.Lock()
 	defer m.Unlock()
+	m.sequence.Add(1)
+	return m.addTenants(nodeID, req, replFactor, v, modifiedAt)
+}
+
+// AddTenantsStrict adds the requested tenants like AddTenants, but fails without adding
+// any tenant if one of them exists already. The class replication factor is used.
+func (m *metaClass) AddTenantsStrict(nodeID string, req *command.AddTenantsRequest, v uint64, modifiedAt time.Time) error {
+	req.Tenants = removeNilTenants(req.Tenants)
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	var existing []string
+	for _, t := range req.Tenants {
+		name := m.tenantName(t.Name)
+		if _, ok := m.Sharding.Physical[name]; ok {
+			existing = append(existing, name)
+		}
+	}
+	if len(existing) > 0 {
+		return fmt.Errorf("%w: %v", ErrTenantExists, existing)
+	}
+	return m.addTenants(nodeID, req, m.replicationFactor(), v, modifiedAt)
+}
+
+// WouldAddTenantsChange returns false if all tenants requested by req exist already.
+// Unlike AddTenants it doesn't modify req.
+func (m *metaClass) WouldAddTenantsChange(req *command.AddTenantsRequest) bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	for _, t := range req.Tenants {
+		if t == nil {
+			continue
+		}
+		if _, ok := m.Sharding.Physical[m.tenantName(t.Name)]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *metaClass) addTenants(nodeID string, req *command.AddTenantsRequest, replFactor int64, v uint64, modifiedAt time.Time) error {
+	if m.TenantCreationSuspended {
+		return ErrTenantCreationSuspended
+	}
+	if err := m.normalizeTenantNames(req.Tenants); err != nil {
+		return err
+	}
+	if err := m.checkTombstones(req.Tenants, modifiedAt); err != nil {
+		return err
+	}
 
 	// TODO-RAFT: Optimize here and avoid iteration twice on the req.Tenants array
-	names := make([]string, len(req.Tenants))
-	for i, tenant := range req.Tenants {
-		names[i] = tenant.Name
+	names := make([]string, 0, len(req.Tenants))
+	for _, tenant := range req.Tenants {
+		if len(tenant.Nodes) == 0 {
+			names = append(names, tenant.Name)
+		}
 	}
 	// First determine the partition based on the node *present at the time of the log entry being created*
-	partitions, err := m.Sharding.GetPartitions(req.ClusterNodes, names, replFactor)
-	if err != nil {
-		return fmt.Errorf("get partitions: %w", err)
+	partitions := map[string][]string{}
+	if len(names) > 0 {
+		var err error
+		if partitions, err = m.Sharding.GetPartitions(req.ClusterNodes, names, replFactor); err != nil {
+			return fmt.Errorf("get partitions: %w", err)
+		}
 	}
 
-	// Iterate over requested tenants and assign them, if found, a partition
+	// Iterate over requested tenants and assign them, if found, a partition.
+	// Tenants whose nodes don't match the replication factor are rejected, the others are still added.
+	rejected := TenantsError{}
 	for i, t := range req.Tenants {
 		if _, ok := m.Sharding.Physical[t.Name]; ok {
 			req.Tenants[i] = nil // already exists
@@ -222,12 +424,21 @@ func (m *metaClass) AddTenants(nodeID string, req *command.AddTenantsRequest, re
 		}
 		// TODO-RAFT: Check in which cases can the partition not have assigned one to a tenant
 		part, ok := partitions[t.Name]
+		if len(t.Nodes) > 0 {
+			part, ok = slices.Clone(t.Nodes), true
+		}
 		if !ok {
 			// TODO-RAFT: Do we want to silently continue here or raise an error ?
 			continue
 		}
-		p := sharding.Physical{Name: t.Name, Status: t.Status, BelongsToNodes: part}
+		if !req.SkipPlacementCheck && !validTenantPlacement(part, replFactor) {
+			rejected[t.Name] = fmt.Errorf("nodes %v don't match replication factor %d", part, replFactor)
+			req.Tenants[i] = nil
+			continue
+		}
+		p := sharding.Physical{Name: t.Name, Status: t.Status, BelongsToNodes: part, LastModifiedUnix: unixMilli(modifiedAt)}
 		m.Sharding.Physical[t.Name] = p
+		m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: t.Name, Type: TenantAdded, Status: p.Status, Version: v})
 		// TODO-RAFT: Check here why we set =nil if it is "owned by another node"
 		if !slices.Contains(part, nodeID) {
 			req.Tenants[i] = nil // is owned by another node
@@ -235,25 +446,44 @@ func (m *metaClass) AddTenants(nodeID string, req *command.AddTenantsRequest, re
 	}
 	m.ShardVersion = v
 	req.Tenants = removeNilTenants(req.Tenants)
+	if len(rejected) > 0 {
+		return rejected
+	}
 	return nil
 }
 
-func (m *metaClass) DeleteTenants(req *command.DeleteTenantsRequest, v uint64) error {
+// DeleteTenants deletes the requested tenants. modifiedAt is recorded as their deletion time.
+// Requests deleting more than MaxTenantDeletes existing tenants fail without deleting any.
+func (m *metaClass) DeleteTenants(req *command.DeleteTenantsRequest, v uint64, modifiedAt time.Time) error {
 	m.Lock()
 	defer m.Unlock()
+	m.sequence.Add(1)
 
-	for _, name := range req.Tenants {
+	if _, err := m.checkTenantDeletes(req); err != nil {
+		return err
+	}
+	for i := range req.Tenants {
+		req.Tenants[i] = m.tenantName(req.Tenants[i])
+		name := req.Tenants[i]
+		if _, ok := m.Sharding.Physical[name]; ok {
+			m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: name, Type: TenantRemoved, Version: v})
+		}
 		m.Sharding.DeletePartition(name)
+		m.Tombstones.add(name, modifiedAt)
 	}
 	m.ShardVersion = v
 	return nil
 }
 
-func (m *metaClass) UpdateTenantsProcess(nodeID string, req *command.TenantProcessRequest, v uint64) error {
+// UpdateTenantsProcess records the result of a freeze/unfreeze process and sets the last modification time of
+// tenants whose status changed to modifiedAt
+func (m *metaClass) UpdateTenantsProcess(nodeID string, req *command.TenantProcessRequest, v uint64, modifiedAt time.Time) error {
 	m.Lock()
 	defer m.Unlock()
+	m.sequence.Add(1)
 
 	for idx := range req.TenantsProcesses {
+		req.TenantsProcesses[idx].Tenant.Name = m.tenantName(req.TenantsProcesses[idx].Tenant.Name)
 		name := req.TenantsProcesses[idx].Tenant.Name
 		shard, ok := m.Sharding.Physical[name]
 		if !ok {
@@ -269,6 +499,7 @@ func (m *metaClass) UpdateTenantsProcess(nodeID string, req *command.TenantProce
 
 		process := m.shardProcess(name, req.Action)
 		process[req.Node] = req.TenantsProcesses[idx]
+		status, nodes := shard.Status, slices.Clone(shard.BelongsToNodes)
 
 		if m.allShardProcessExecuted(name, req.Action) {
 			m.applyShardProcess(name, req.Action, req.TenantsProcesses[idx], &shard)
@@ -280,6 +511,13 @@ func (m *metaClass) UpdateTenantsProcess(nodeID string, req *command.TenantProce
 			}
 		}
 
+		if shard.Status != status {
+			shard.LastModifiedUnix = unixMilli(modifiedAt)
+		}
+		// processes which are still waiting for other nodes don't change the tenant
+		if shard.Status != status || !slices.Equal(shard.BelongsToNodes, nodes) {
+			m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: shard.Name, Type: TenantUpdated, Status: shard.Status, Version: v})
+		}
 		m.ShardVersion = v
 		m.Sharding.Physical[shard.Name] = shard
 		if !slices.Contains(shard.BelongsToNodes, nodeID) {
@@ -290,46 +528,45 @@ func (m *metaClass) UpdateTenantsProcess(nodeID string, req *command.TenantProce
 	return nil
 }
 
-func (m *metaClass) UpdateTenants(nodeID string, req *command.UpdateTenantsRequest, v uint64) error {
+// UpdateTenants updates the status of the requested tenants and sets their last modification time to modifiedAt.
+// Every status change is added to the status history of its tenant together with the reason of req.
+func (m *metaClass) UpdateTenants(nodeID string, req *command.UpdateTenantsRequest, v uint64, modifiedAt time.Time) error {
 	m.Lock()
 	defer m.Unlock()
+	m.sequence.Add(1)
+
+	return m.updateTenants(nodeID, req, v, modifiedAt)
+}
 
-	// For each requested tenant update we'll check if we the schema is missing that shard. If we have any missing shard
-	// we'll return an error but any other successful shard will be updated.
-	// If we're not adding a new shard we'll then check if the activity status needs to be changed
+// updateTenants implements UpdateTenants, the caller must hold the write lock
+func (m *metaClass) updateTenants(nodeID string, req *command.UpdateTenantsRequest, v uint64, modifiedAt time.Time) error {
+	// For each requested tenant update we'll check if the schema is missing that shard or if the transition isn't
+	// allowed. Such tenants are reported in a TenantsError but any other tenant of the request will be updated.
 	// If the activity status is changed we will deep copy the tenant and update the status
-	missingShards := []string{}
+	rejected := TenantsError{}
 	writeIndex := 0
 	for i, requestTenant := range req.Tenants {
+		requestTenant.Name = m.tenantName(requestTenant.Name)
 		schemaTenant, ok := m.Sharding.Physical[requestTenant.Name]
-		// If we can't find the shard add it to missing shards to error later
+		// If we can't find the shard add it to the rejected tenants to error later
 		if !ok {
-			missingShards = append(missingShards, requestTenant.Name)
+			rejected[requestTenant.Name] = ErrShardNotFound
+			continue
+		}
+
+		if req.Owner != "" && !slices.Contains(schemaTenant.BelongsToNodes, req.Owner) {
+			rejected[requestTenant.Name] = fmt.Errorf("%w: node %q", ErrNotTenantOwner, req.Owner)
 			continue
 		}
 
 		// validate status
-		switch schemaTenant.ActivityStatus() {
-		case req.Tenants[i].Status:
+		noop, reason := m.checkStatusTransition(schemaTenant, requestTenant.Status)
+		if reason != "" {
+			rejected[requestTenant.Name] = fmt.Errorf("%w: %s", ErrInvalidStatusTransition, reason)
+			continue
+		}
+		if noop {
 			continue
-		case types.TenantActivityStatusFREEZING:
-			// ignore multiple freezing
-			if requestTenant.Status == models.TenantActivityStatusFROZEN {
-				continue
-			}
-		case types.TenantActivityStatusUNFREEZING:
-			// ignore multiple unfreezing
-			var statusInProgress string
-			processes, exists := m.ShardProcesses[shardProcessID(req.Tenants[i].Name, command.TenantProcessRequest_ACTION_UNFREEZING)]
-			if exists {
-				for _, process := range processes {
-					statusInProgress = process.Tenant.Status
-					break
-				}
-			}
-			if requestTenant.Status == statusInProgress {
-				continue
-			}
 		}
 
 		existedSharedFrozen := schemaTenant.ActivityStatus() == models.TenantActivityStatusFROZEN || schemaTenant.ActivityStatus() == models.TenantActivityStatusFREEZING
@@ -352,10 +589,17 @@ func (m *metaClass) UpdateTenants(nodeID string, req *command.UpdateTenantsReque
 
 		schemaTenant = schemaTenant.DeepCopy()
 		schemaTenant.Status = requestTenant.Status
+		schemaTenant.LastModifiedUnix = unixMilli(modifiedAt)
+		schemaTenant.AddStatusChange(sharding.StatusChange{
+			Status:       requestTenant.Status,
+			Reason:       req.Reason,
+			ModifiedUnix: schemaTenant.LastModifiedUnix,
+		})
 
 		// Update the schema tenant representation with the deep copy (necessary as the initial is a shallow copy from
 		// the map read
 		m.Sharding.Physical[schemaTenant.Name] = schemaTenant
+		m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: schemaTenant.Name, Type: TenantUpdated, Status: schemaTenant.Status, Version: v})
 
 		// If the shard is not stored on that node skip updating the request tenant as there will be nothing to load on
 		// the DB side
@@ -372,21 +616,28 @@ func (m *metaClass) UpdateTenants(nodeID string, req *command.UpdateTenantsReque
 	// Remove the ignore tenants from the request to act as filter on the subsequent DB update
 	req.Tenants = req.Tenants[:writeIndex]
 
-	// Check for any missing shard to return an error
-	var err error
-	if len(missingShards) > 0 {
-		err = fmt.Errorf("%w: %v", ErrShardNotFound, missingShards)
-	}
 	// Update the version of the shard to the current version
 	m.ShardVersion = v
 
-	return err
+	if len(rejected) > 0 {
+		return rejected
+	}
+	return nil
+}
+
+// unixMilli returns t in unix milliseconds, or zero if t is the zero time
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
 }
 
 // LockGuard provides convenient mechanism for owning mutex by function which mutates the state.
 func (m *metaClass) LockGuard(mutator func(*metaClass) error) error {
 	m.Lock()
 	defer m.Unlock()
+	m.sequence.Add(1)
 	return mutator(m)
 }
 
@@ -525,7 +776,7 @@ func (m *metaClass) unfreeze(nodeID string, i int, req *command.UpdateTenantsReq
 	name := req.Tenants[i].Name
 	process := m.shardProcess(name, command.TenantProcessRequest_ACTION_UNFREEZING)
 
-	partitions, err := m.Sharding.GetPartitions(req.ClusterNodes, []string{name}, m.Class.ReplicationConfig.Factor)
+	partitions, err := m.Sharding.GetPartitions(req.ClusterNodes, []string{name}, m.tenantReplicationFactor(p))
 	if err != nil {
 		req.Tenants[i] = nil
 		return fmt.Errorf("get partitions: %w", err)
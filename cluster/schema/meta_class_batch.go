@@ -0,0 +1,243 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/cluster/types"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+	"golang.org/x/exp/slices"
+)
+
+// TenantSpec describes a tenant to be created by a TenantBatch
+type TenantSpec struct {
+	Name   string
+	Status string
+	Nodes  []string
+}
+
+// TenantBatch groups tenant operations of mixed types to be applied at once.
+// A tenant can be part of one operation per batch only.
+type TenantBatch struct {
+	Creates []TenantSpec
+	Deletes []string
+	// Updates maps tenant names to their new status
+	Updates map[string]string
+	// SkipPlacementCheck allows creating tenants on a number of nodes other than the class replication factor
+	SkipPlacementCheck bool
+}
+
+// TenantOpResult is the outcome of a single operation of a TenantBatch
+type TenantOpResult struct {
+	Tenant string
+	Type   TenantChangeType
+	// Local is true if the tenant is owned by the node applying the batch and the operation changed it
+	Local bool
+	Err   error
+}
+
+// TenantBatchResult reports the outcome of every operation of a TenantBatch
+// in the order creates, deletes, updates
+type TenantBatchResult struct {
+	Results []TenantOpResult
+}
+
+// ApplyTenantBatch validates all operations of ops and applies them under a single lock.
+// If any operation is invalid, nothing is applied and the returned error joins the errors of
+// the invalid operations, which are also reported in the result.
+// Updates requiring a tenant to be frozen or unfrozen are not supported and have to go through
+// UpdateTenants. The operations are recorded in the tenant change log at version v.
+// modifiedAt is the time the batch is applied at, which tombstones are checked and recorded with.
+func (m *metaClass) ApplyTenantBatch(nodeID string, ops TenantBatch, v uint64, modifiedAt time.Time) (TenantBatchResult, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	return m.applyTenantBatch(nodeID, ops, v, modifiedAt)
+}
+
+// EnsureTenantBatch returns the batch which creates the specified tenant with status and nodes if it
+// doesn't exist and otherwise updates its status if it differs. The batch is empty if nothing has
+// to change. The state is not mutated; the batch is applied with ApplyTenantBatch.
+func (m *metaClass) EnsureTenantBatch(tenant, status string, nodes []string) TenantBatch {
+	m.RLock()
+	defer m.RUnlock()
+
+	var ops TenantBatch
+	if p, ok := m.Sharding.Physical[m.tenantName(tenant)]; !ok {
+		ops.Creates = []TenantSpec{{Name: tenant, Status: status, Nodes: nodes}}
+	} else if p.ActivityStatus() != status {
+		ops.Updates = map[string]string{tenant: status}
+	}
+	return ops
+}
+
+func (m *metaClass) applyTenantBatch(nodeID string, ops TenantBatch, v uint64, modifiedAt time.Time) (TenantBatchResult, error) {
+	var res TenantBatchResult
+	seen := make(map[string]struct{}, len(ops.Creates)+len(ops.Deletes)+len(ops.Updates))
+	validate := func(name string, typ TenantChangeType, check func(name string) error) {
+		name = m.tenantName(name)
+		err := check(name)
+		if _, ok := seen[name]; ok && err == nil {
+			err = fmt.Errorf("tenant %q is part of multiple operations", name)
+		}
+		seen[name] = struct{}{}
+		res.Results = append(res.Results, TenantOpResult{Tenant: name, Type: typ, Err: err})
+	}
+
+	for _, spec := range ops.Creates {
+		validate(spec.Name, TenantAdded, func(name string) error {
+			_, exists := m.Sharding.Physical[name]
+			switch {
+			case m.TenantCreationSuspended:
+				return ErrTenantCreationSuspended
+			case exists:
+				return fmt.Errorf("tenant %q already exists", name)
+			case len(m.Tombstones.Deleted) > 0 && m.Tombstones.contains(name, modifiedAt):
+				return fmt.Errorf("%w: %s", ErrTenantTombstoned, name)
+			case len(spec.Nodes) == 0:
+				return fmt.Errorf("tenant %q: list of nodes is empty", name)
+			case !ops.SkipPlacementCheck && m.Class.ReplicationConfig != nil &&
+				!validTenantPlacement(spec.Nodes, m.Class.ReplicationConfig.Factor):
+				return fmt.Errorf("tenant %q: nodes %v don't match replication factor %d", name, spec.Nodes, m.Class.ReplicationConfig.Factor)
+			case spec.Status != "" && spec.Status != models.TenantActivityStatusHOT && spec.Status != models.TenantActivityStatusCOLD:
+				return fmt.Errorf("tenant %q: can't be created with status %q", name, spec.Status)
+			}
+			return nil
+		})
+	}
+	for _, name := range ops.Deletes {
+		validate(name, TenantRemoved, func(name string) error {
+			if _, ok := m.Sharding.Physical[name]; !ok {
+				return fmt.Errorf("%w: %s", ErrShardNotFound, name)
+			}
+			return nil
+		})
+	}
+	updates := make([]string, 0, len(ops.Updates))
+	for name := range ops.Updates {
+		updates = append(updates, name)
+	}
+	slices.Sort(updates)
+	for _, name := range updates {
+		status := ops.Updates[name]
+		validate(name, TenantUpdated, func(name string) error {
+			p, ok := m.Sharding.Physical[name]
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrShardNotFound, name)
+			}
+			if _, reason := m.checkStatusTransition(p, status); reason != "" {
+				return fmt.Errorf("%w: %s: %s", ErrInvalidStatusTransition, name, reason)
+			}
+			if requiresOffloading(p.ActivityStatus(), status) {
+				return fmt.Errorf("tenant %q: freezing and unfreezing isn't supported in a batch", name)
+			}
+			return nil
+		})
+	}
+
+	var errs []error
+	for _, r := range res.Results {
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	if len(errs) > 0 {
+		return res, errors.Join(errs...)
+	}
+
+	// all operations are valid, results are in the same order as the operations
+	results := res.Results
+	for i, spec := range ops.Creates {
+		r := &results[i]
+		m.Sharding.Physical[r.Tenant] = sharding.Physical{
+			Name:             r.Tenant,
+			Status:           spec.Status,
+			BelongsToNodes:   slices.Clone(spec.Nodes),
+			LastModifiedUnix: unixMilli(modifiedAt),
+		}
+		m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: r.Tenant, Type: TenantAdded, Status: spec.Status, Version: v})
+		r.Local = slices.Contains(spec.Nodes, nodeID)
+	}
+	results = results[len(ops.Creates):]
+	for i := range ops.Deletes {
+		r := &results[i]
+		r.Local = slices.Contains(m.Sharding.Physical[r.Tenant].BelongsToNodes, nodeID)
+		m.Sharding.DeletePartition(r.Tenant)
+		m.Tombstones.add(r.Tenant, modifiedAt)
+		m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: r.Tenant, Type: TenantRemoved, Version: v})
+	}
+	results = results[len(ops.Deletes):]
+	for i, name := range updates {
+		r := &results[i]
+		if noop, _ := m.checkStatusTransition(m.Sharding.Physical[r.Tenant], ops.Updates[name]); noop {
+			continue // neither the schema nor the DB change
+		}
+		p := m.Sharding.Physical[r.Tenant].DeepCopy()
+		p.Status = ops.Updates[name]
+		p.LastModifiedUnix = unixMilli(modifiedAt)
+		m.Sharding.Physical[r.Tenant] = p
+		m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: r.Tenant, Type: TenantUpdated, Status: p.Status, Version: v})
+		r.Local = slices.Contains(p.BelongsToNodes, nodeID)
+	}
+	m.ShardVersion = v
+	return res, nil
+}
+
+// localRequests returns the requests the DB has to apply for the operations of ops owned by the local node.
+// res must be the result of successfully applying ops.
+func (res TenantBatchResult) localRequests(ops TenantBatch) (*command.AddTenantsRequest, *command.DeleteTenantsRequest, *command.UpdateTenantsRequest) {
+	adds, deletes, updates := &command.AddTenantsRequest{}, &command.DeleteTenantsRequest{}, &command.UpdateTenantsRequest{}
+	if len(res.Results) != len(ops.Creates)+len(ops.Deletes)+len(ops.Updates) {
+		return adds, deletes, updates
+	}
+
+	results := res.Results
+	for i, spec := range ops.Creates {
+		if results[i].Local {
+			adds.Tenants = append(adds.Tenants, &command.Tenant{Name: results[i].Tenant, Status: spec.Status})
+		}
+	}
+	results = results[len(ops.Creates):]
+	for i := range ops.Deletes {
+		if results[i].Local {
+			deletes.Tenants = append(deletes.Tenants, results[i].Tenant)
+		}
+	}
+	results = results[len(ops.Deletes):]
+	names := make([]string, 0, len(ops.Updates))
+	for name := range ops.Updates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for i, name := range names {
+		if results[i].Local {
+			updates.Tenants = append(updates.Tenants, &command.Tenant{Name: results[i].Tenant, Status: ops.Updates[name]})
+		}
+	}
+	return adds, deletes, updates
+}
+
+// requiresOffloading returns true if changing the status from one to another
+// requires a tenant to be frozen or unfrozen
+func requiresOffloading(from, to string) bool {
+	frozen := func(status string) bool {
+		return status == models.TenantActivityStatusFROZEN || status == types.TenantActivityStatusFREEZING ||
+			status == types.TenantActivityStatusUNFREEZING
+	}
+	return from != to && (frozen(from) || frozen(to))
+}
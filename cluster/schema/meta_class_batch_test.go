@@ -0,0 +1,132 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassApplyTenantBatch(t *testing.T) {
+	newMetaClass := func() *metaClass {
+		return &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+			"T2": {Name: "T2", BelongsToNodes: []string{"B"}, Status: models.TenantActivityStatusHOT},
+			"T3": {Name: "T3", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusFROZEN},
+		}}}
+	}
+
+	t.Run("valid batch", func(t *testing.T) {
+		m := newMetaClass()
+		res, err := m.ApplyTenantBatch("A", TenantBatch{
+			Creates: []TenantSpec{{Name: "T4", Status: models.TenantActivityStatusCOLD, Nodes: []string{"B"}}},
+			Deletes: []string{"T1"},
+			Updates: map[string]string{"T2": models.TenantActivityStatusCOLD},
+		}, 2, time.UnixMilli(3))
+		require.Nil(t, err)
+		assert.Equal(t, []TenantOpResult{
+			{Tenant: "T4", Type: TenantAdded},
+			{Tenant: "T1", Type: TenantRemoved, Local: true},
+			{Tenant: "T2", Type: TenantUpdated},
+		}, res.Results)
+		assert.Equal(t, sharding.Physical{
+			Name: "T4", BelongsToNodes: []string{"B"}, Status: models.TenantActivityStatusCOLD, LastModifiedUnix: 3,
+		}, m.Sharding.Physical["T4"])
+		assert.NotContains(t, m.Sharding.Physical, "T1")
+		assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T2"].Status)
+
+		changes, _ := m.TenantChangesSince(0)
+		assert.Equal(t, []TenantChange{
+			{Tenant: "T4", Type: TenantAdded, Status: models.TenantActivityStatusCOLD, Version: 2},
+			{Tenant: "T1", Type: TenantRemoved, Version: 2},
+			{Tenant: "T2", Type: TenantUpdated, Status: models.TenantActivityStatusCOLD, Version: 2},
+		}, changes)
+		assert.Equal(t, uint64(2), m.ShardVersion)
+	})
+
+	t.Run("local requests", func(t *testing.T) {
+		m := newMetaClass()
+		ops := TenantBatch{
+			Creates: []TenantSpec{{Name: "T4", Status: models.TenantActivityStatusCOLD, Nodes: []string{"A"}}},
+			Deletes: []string{"T2"},
+			Updates: map[string]string{"T1": models.TenantActivityStatusCOLD},
+		}
+		res, err := m.ApplyTenantBatch("A", ops, 1, time.Time{})
+		require.Nil(t, err)
+		adds, deletes, updates := res.localRequests(ops)
+		assert.Equal(t, []*command.Tenant{{Name: "T4", Status: models.TenantActivityStatusCOLD}}, adds.Tenants)
+		assert.Empty(t, deletes.Tenants, "T2 belongs to another node")
+		assert.Equal(t, []*command.Tenant{{Name: "T1", Status: models.TenantActivityStatusCOLD}}, updates.Tenants)
+	})
+
+	tests := []struct {
+		name  string
+		batch TenantBatch
+	}{
+		{"existing tenant", TenantBatch{Creates: []TenantSpec{{Name: "T1", Nodes: []string{"A"}}}}},
+		{"no nodes", TenantBatch{Creates: []TenantSpec{{Name: "T4"}}}},
+		{"frozen creation", TenantBatch{Creates: []TenantSpec{{Name: "T4", Nodes: []string{"A"}, Status: models.TenantActivityStatusFROZEN}}}},
+		{"missing delete", TenantBatch{Deletes: []string{"X"}}},
+		{"missing update", TenantBatch{Updates: map[string]string{"X": models.TenantActivityStatusCOLD}}},
+		{"unfreezing", TenantBatch{Updates: map[string]string{"T3": models.TenantActivityStatusHOT}}},
+		{"freezing", TenantBatch{Updates: map[string]string{"T2": models.TenantActivityStatusFROZEN}}},
+		{"multiple operations", TenantBatch{Deletes: []string{"T1"}, Updates: map[string]string{"T1": models.TenantActivityStatusCOLD}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMetaClass()
+			tc.batch.Deletes = append(tc.batch.Deletes, "T2")
+			res, err := m.ApplyTenantBatch("A", tc.batch, 1, time.Time{})
+			assert.NotNil(t, err)
+			failed := 0
+			for _, r := range res.Results {
+				if r.Err != nil {
+					failed++
+				}
+			}
+			assert.Equal(t, 1, failed)
+			assert.Equal(t, newMetaClass().Sharding, m.Sharding, "nothing is applied")
+		})
+	}
+}
+
+func TestMetaClassEnsureTenantBatch(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+	}}}
+
+	ops := m.EnsureTenantBatch("T2", models.TenantActivityStatusCOLD, []string{"A", "B"})
+	assert.Equal(t, TenantBatch{Creates: []TenantSpec{{Name: "T2", Status: models.TenantActivityStatusCOLD, Nodes: []string{"A", "B"}}}}, ops)
+	assert.NotContains(t, m.Sharding.Physical, "T2", "planning is read-only")
+	res, err := m.ApplyTenantBatch("A", ops, 1, time.Time{})
+	require.Nil(t, err)
+	assert.Equal(t, []TenantOpResult{{Tenant: "T2", Type: TenantAdded, Local: true}}, res.Results)
+
+	ops = m.EnsureTenantBatch("T1", models.TenantActivityStatusCOLD, nil)
+	assert.Equal(t, TenantBatch{Updates: map[string]string{"T1": models.TenantActivityStatusCOLD}}, ops)
+	_, err = m.ApplyTenantBatch("A", ops, 2, time.Time{})
+	require.Nil(t, err)
+	assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T1"].Status)
+
+	// nothing to do
+	assert.Equal(t, TenantBatch{}, m.EnsureTenantBatch("T1", models.TenantActivityStatusCOLD, nil))
+
+	_, err = m.ApplyTenantBatch("A", m.EnsureTenantBatch("T3", models.TenantActivityStatusHOT, nil), 3, time.Time{})
+	assert.NotNil(t, err)
+	assert.NotContains(t, m.Sharding.Physical, "T3")
+}
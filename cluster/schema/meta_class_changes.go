@@ -0,0 +1,105 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import "github.com/weaviate/weaviate/usecases/sharding"
+
+// maxTenantChanges bounds the number of tenant changes kept per class
+const maxTenantChanges = 1024
+
+type TenantChangeType string
+
+const (
+	TenantAdded   TenantChangeType = "ADDED"
+	TenantUpdated TenantChangeType = "UPDATED"
+	TenantRemoved TenantChangeType = "REMOVED"
+	// TenantResync signals that the requested version is too old
+	// and the consumer has to fetch the full sharding state again
+	TenantResync TenantChangeType = "RESYNC"
+)
+
+// TenantChange describes a single tenant mutation applied at Version
+type TenantChange struct {
+	Tenant  string
+	Type    TenantChangeType
+	Status  string
+	Version uint64
+}
+
+// tenantChangeLog is a bounded log of tenant changes.
+// It holds every change applied after version since.
+type tenantChangeLog struct {
+	started bool
+	since   uint64
+	changes []TenantChange
+}
+
+// record appends c to the log. current is the shard version before c is applied.
+func (l *tenantChangeLog) record(current uint64, c TenantChange) {
+	if !l.started {
+		l.started = true
+		l.since = current
+	}
+	if len(l.changes) == maxTenantChanges {
+		l.since = l.changes[0].Version
+		l.changes = append(l.changes[:0], l.changes[1:]...)
+	}
+	l.changes = append(l.changes, c)
+}
+
+// TenantChangesSince returns the tenant changes applied after the specified version and the current version.
+// Consumers initially sync using CopyShardingState and then poll using the last returned version.
+// If the version is too old to be served from the change log, a single TenantResync change is returned.
+// Every tenant mutation applied through raft is recorded, requests which don't change a tenant aren't.
+func (m *metaClass) TenantChangesSince(version uint64) (changes []TenantChange, currentVersion uint64) {
+	m.RLock()
+	defer m.RUnlock()
+
+	currentVersion = m.version()
+	if version >= currentVersion {
+		return nil, currentVersion
+	}
+
+	l := &m.tenantChanges
+	if !l.started || version < l.since {
+		return []TenantChange{{Type: TenantResync, Version: currentVersion}}, currentVersion
+	}
+	for _, c := range l.changes {
+		if c.Version > version {
+			changes = append(changes, c)
+		}
+	}
+	return changes, currentVersion
+}
+
+// TenantCountDelta returns the number of tenants present only in after and the number
+// of tenants present only in before. A nil state is treated as empty.
+func TenantCountDelta(before, after *sharding.State) (added, removed int) {
+	var prev, next map[string]sharding.Physical
+	if before != nil {
+		prev = before.Physical
+	}
+	if after != nil {
+		next = after.Physical
+	}
+	for name := range next {
+		if _, ok := prev[name]; !ok {
+			added++
+		}
+	}
+	for name := range prev {
+		if _, ok := next[name]; !ok {
+			removed++
+		}
+	}
+	return added, removed
+}
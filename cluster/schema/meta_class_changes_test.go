@@ -0,0 +1,97 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassTenantChangesSince(t *testing.T) {
+	m := &metaClass{
+		Class:        models.Class{Class: "C"},
+		Sharding:     sharding.State{Physical: map[string]sharding.Physical{}, PartitioningEnabled: true},
+		ClassVersion: 1,
+		ShardVersion: 1,
+	}
+
+	changes, v := m.TenantChangesSince(1)
+	assert.Empty(t, changes)
+	assert.Equal(t, uint64(1), v)
+
+	req := &command.AddTenantsRequest{
+		ClusterNodes: []string{"A"},
+		Tenants:      []*command.Tenant{{Name: "T1", Status: models.TenantActivityStatusHOT}},
+	}
+	require.Nil(t, m.AddTenants("A", req, 1, 2, time.Time{}))
+	require.Nil(t, m.DeleteTenants(&command.DeleteTenantsRequest{Tenants: []string{"T1", "X"}}, 3, time.Time{}))
+
+	changes, v = m.TenantChangesSince(1)
+	assert.Equal(t, uint64(3), v)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "T1", Type: TenantAdded, Status: models.TenantActivityStatusHOT, Version: 2},
+		{Tenant: "T1", Type: TenantRemoved, Version: 3},
+	}, changes)
+
+	changes, _ = m.TenantChangesSince(2)
+	assert.Equal(t, []TenantChange{{Tenant: "T1", Type: TenantRemoved, Version: 3}}, changes)
+
+	// versions older than the log require a full resync
+	changes, _ = m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{{Type: TenantResync, Version: 3}}, changes)
+}
+
+func TestMetaClassTenantChangesOnlyChanges(t *testing.T) {
+	m := &metaClass{
+		Class: models.Class{Class: "C"},
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+		}, PartitioningEnabled: true},
+		ShardVersion: 1,
+	}
+
+	require.Nil(t, m.SetTenantReplicationFactor("T1", 1, []string{"A"}, 2))
+
+	_, err := m.ApplyTenantBatch("A", TenantBatch{Updates: map[string]string{"T1": models.TenantActivityStatusHOT}}, 3, time.Time{})
+	require.Nil(t, err)
+	require.Nil(t, m.SetTenantReplicationFactor("T1", 1, []string{"A"}, 4))
+	changes, _ := m.TenantChangesSince(2)
+	assert.Empty(t, changes)
+
+	changes, _ = m.TenantChangesSince(1)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "T1", Type: TenantUpdated, Status: models.TenantActivityStatusHOT, Version: 2},
+	}, changes)
+}
+
+func TestTenantCountDelta(t *testing.T) {
+	before := &sharding.State{Physical: map[string]sharding.Physical{"T1": {}, "T2": {}, "T3": {}}}
+	after := &sharding.State{Physical: map[string]sharding.Physical{"T2": {}, "T4": {}}}
+
+	added, removed := TenantCountDelta(before, after)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 2, removed)
+
+	added, removed = TenantCountDelta(nil, after)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 0, removed)
+
+	added, removed = TenantCountDelta(before, nil)
+	assert.Equal(t, 0, added)
+	assert.Equal(t, 3, removed)
+}
@@ -0,0 +1,117 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+// weights of the health score components, they sum up to 100
+const (
+	healthWeightUnderReplicated = 30
+	healthWeightOrphaned        = 40
+	healthWeightSkew            = 20
+	healthWeightInvalidStatus   = 10
+)
+
+// HealthComponents are the inputs of the health score of a class
+type HealthComponents struct {
+	Shards int
+	// UnderReplicated is the number of shards with less live replicas than their replication factor
+	UnderReplicated int
+	// Orphaned is the number of shards without any live replica
+	Orphaned int
+	// PrimarySkew is the difference between the highest and the lowest number
+	// of primary shards of a live node
+	PrimarySkew int
+	// MaxPrimaries is the highest number of primary shards of a live node
+	MaxPrimaries int
+	// InvalidStatus is the number of shards whose status is not a known tenant status
+	InvalidStatus int
+}
+
+// Score returns a health score between 0 and 100, 100 being perfectly healthy.
+// Each component reduces the score by its weight times the affected fraction:
+// under replicated shards by up to 30, orphaned shards by up to 40, shards with an
+// invalid status by up to 10 and the primary skew relative to MaxPrimaries by up to 20.
+// A class without shards is perfectly healthy.
+func (h HealthComponents) Score() int {
+	if h.Shards == 0 {
+		return 100
+	}
+	penalty := float64(healthWeightUnderReplicated*h.UnderReplicated+
+		healthWeightOrphaned*h.Orphaned+
+		healthWeightInvalidStatus*h.InvalidStatus) / float64(h.Shards)
+	if h.MaxPrimaries > 0 {
+		penalty += float64(healthWeightSkew*h.PrimarySkew) / float64(h.MaxPrimaries)
+	}
+	score := 100 - int(penalty+0.5)
+	if score < 0 {
+		return 0
+	}
+	return score
+}
+
+// HealthBreakdown returns the components of the health score of the class
+// with respect to the nodes which are set in liveNodes
+func (m *metaClass) HealthBreakdown(liveNodes map[string]bool) HealthComponents {
+	m.RLock()
+	defer m.RUnlock()
+	return m.healthBreakdown(liveNodes)
+}
+
+func (m *metaClass) healthBreakdown(liveNodes map[string]bool) HealthComponents {
+	h := HealthComponents{Shards: len(m.Sharding.Physical)}
+	primaries := make(map[string]int)
+	for node, live := range liveNodes {
+		if live {
+			primaries[node] = 0
+		}
+	}
+	for _, p := range m.Sharding.Physical {
+		live := make(map[string]struct{}, len(p.BelongsToNodes))
+		for _, node := range p.BelongsToNodes {
+			if liveNodes[node] {
+				live[node] = struct{}{}
+			}
+		}
+		if len(live) == 0 {
+			h.Orphaned++
+		}
+		if int64(len(live)) < m.tenantReplicationFactor(&p) {
+			h.UnderReplicated++
+		}
+		if !isKnownTenantStatus(p.ActivityStatus()) {
+			h.InvalidStatus++
+		}
+		if len(p.BelongsToNodes) > 0 && liveNodes[p.BelongsToNodes[0]] {
+			primaries[p.BelongsToNodes[0]]++
+		}
+	}
+	if len(primaries) > 0 {
+		lowest := -1
+		for _, n := range primaries {
+			if n > h.MaxPrimaries {
+				h.MaxPrimaries = n
+			}
+			if lowest < 0 || n < lowest {
+				lowest = n
+			}
+		}
+		h.PrimarySkew = h.MaxPrimaries - lowest
+	}
+	return h
+}
+
+// HealthScore returns the health score of the class between 0 and 100 with respect to the
+// nodes which are set in liveNodes. See HealthComponents.Score for the weighting.
+func (m *metaClass) HealthScore(liveNodes map[string]bool) int {
+	m.RLock()
+	defer m.RUnlock()
+	return m.healthBreakdown(liveNodes).Score()
+}
@@ -0,0 +1,53 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassHealthScore(t *testing.T) {
+	m := &metaClass{}
+	assert.Equal(t, 100, m.HealthScore(nil))
+
+	live := map[string]bool{"A": true, "B": true, "C": false}
+	m = &metaClass{
+		Class: models.Class{ReplicationConfig: &models.ReplicationConfig{Factor: 2}},
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusHOT},
+			"S2": {Name: "S2", BelongsToNodes: []string{"B", "A"}, Status: models.TenantActivityStatusCOLD},
+		}},
+	}
+	assert.Equal(t, HealthComponents{Shards: 2, MaxPrimaries: 1}, m.HealthBreakdown(live))
+	assert.Equal(t, 100, m.HealthScore(live))
+
+	m.Sharding.Physical["S3"] = sharding.Physical{Name: "S3", BelongsToNodes: []string{"A", "C"}}
+	m.Sharding.Physical["S4"] = sharding.Physical{Name: "S4", BelongsToNodes: []string{"C"}, Status: "WARM"}
+	h := m.HealthBreakdown(live)
+	assert.Equal(t, HealthComponents{
+		Shards:          4,
+		UnderReplicated: 2,
+		Orphaned:        1,
+		PrimarySkew:     1,
+		MaxPrimaries:    2,
+		InvalidStatus:   1,
+	}, h)
+	// 100 - round(30*2/4 + 40*1/4 + 10*1/4 + 20*1/2)
+	assert.Equal(t, 62, h.Score())
+	assert.Equal(t, 62, m.HealthScore(live))
+
+	assert.Equal(t, 0, HealthComponents{Shards: 1, UnderReplicated: 1, Orphaned: 1, InvalidStatus: 1, PrimarySkew: 1, MaxPrimaries: 1}.Score())
+}
@@ -0,0 +1,259 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/weaviate/weaviate/entities/models"
+	"golang.org/x/exp/slices"
+)
+
+// SetMaxPropertyCount sets the maximum number of properties of the class and its version to v.
+// A limit of 0 removes the limit. The limit can't be lower than the current number of properties.
+func (m *metaClass) SetMaxPropertyCount(limit int, v uint64) error {
+	if limit < 0 {
+		return fmt.Errorf("negative property limit: %d", limit)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	if n := len(m.Class.Properties); limit > 0 && limit < n {
+		return fmt.Errorf("property limit %d is lower than current number of properties %d", limit, n)
+	}
+	m.MaxProperties = limit
+	m.ClassVersion = v
+	return nil
+}
+
+// checkPropertyCount checks that adding props doesn't exceed the maximum property count.
+// Properties which already exist don't count.
+func (m *metaClass) checkPropertyCount(props []*models.Property) error {
+	if m.MaxProperties <= 0 {
+		return nil
+	}
+	names := make(map[string]struct{}, len(m.Class.Properties)+len(props))
+	for _, p := range m.Class.Properties {
+		names[strings.ToLower(p.Name)] = struct{}{}
+	}
+	for _, p := range props {
+		names[strings.ToLower(p.Name)] = struct{}{}
+	}
+	if len(names) > m.MaxProperties {
+		return fmt.Errorf("%w: limit is %d", ErrTooManyProperties, m.MaxProperties)
+	}
+	return nil
+}
+
+// recordPropertyVersions records the raft log index v as the version of every property
+// in props which doesn't exist yet
+func (m *metaClass) recordPropertyVersions(v uint64, props []*models.Property) {
+	existing := make(map[string]struct{}, len(m.Class.Properties))
+	for _, p := range m.Class.Properties {
+		existing[strings.ToLower(p.Name)] = struct{}{}
+	}
+	for _, p := range props {
+		name := strings.ToLower(p.Name)
+		if _, ok := existing[name]; ok {
+			continue
+		}
+		if m.PropertyVersions == nil {
+			m.PropertyVersions = make(map[string]uint64)
+		}
+		m.PropertyVersions[name] = v
+	}
+}
+
+// PropertiesAddedAfter returns in class order the names of the properties added after
+// raft log index v. Properties present at class creation have version 0.
+func (m *metaClass) PropertiesAddedAfter(v uint64) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for _, p := range m.Class.Properties {
+		if m.PropertyVersions[strings.ToLower(p.Name)] > v {
+			res = append(res, p.Name)
+		}
+	}
+	return res
+}
+
+// IsBreakingPropertyChange returns true if updating the stored property name to updated
+// requires reindexing, which is the case if its data type or tokenization changes.
+func (m *metaClass) IsBreakingPropertyChange(name string, updated models.Property) (bool, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	for _, p := range m.Class.Properties {
+		if strings.EqualFold(p.Name, name) {
+			return !slices.Equal(p.DataType, updated.DataType) || p.Tokenization != updated.Tokenization, nil
+		}
+	}
+	return false, fmt.Errorf("property %q not found", name)
+}
+
+// PropertyNames returns the sorted names of the properties of the class
+func (m *metaClass) PropertyNames() []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	names := make([]string, len(m.Class.Properties))
+	for i, p := range m.Class.Properties {
+		names[i] = p.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConflictingDuplicateProperties returns the lower cased names of the properties
+// which occur more than once, compared case-insensitively, mapped to their number of occurrences.
+func (m *metaClass) ConflictingDuplicateProperties() map[string]int {
+	m.RLock()
+	defer m.RUnlock()
+
+	counts := make(map[string]int, len(m.Class.Properties))
+	for _, p := range m.Class.Properties {
+		counts[strings.ToLower(p.Name)]++
+	}
+	for name, n := range counts {
+		if n < 2 {
+			delete(counts, name)
+		}
+	}
+	return counts
+}
+
+// dedupeProperties splits the properties into the properties to keep and the names of the
+// properties whose name, compared case-insensitively, was already used by a previous property.
+// The caller must hold the lock.
+func (m *metaClass) dedupeProperties() (kept []*models.Property, removed []string) {
+	seen := make(map[string]struct{}, len(m.Class.Properties))
+	kept = make([]*models.Property, 0, len(m.Class.Properties))
+	for _, p := range m.Class.Properties {
+		name := strings.ToLower(p.Name)
+		if _, ok := seen[name]; ok {
+			removed = append(removed, p.Name)
+			continue
+		}
+		seen[name] = struct{}{}
+		kept = append(kept, p)
+	}
+	return kept, removed
+}
+
+// DuplicateProperties returns in class order the names of the properties which DedupeProperties would remove
+func (m *metaClass) DuplicateProperties() []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	_, removed := m.dedupeProperties()
+	return removed
+}
+
+// DedupeProperties removes every property whose name, compared case-insensitively, was already used
+// by a previous property, keeping the order of the remaining properties. The class version is set to v
+// if any property is removed.
+func (m *metaClass) DedupeProperties(v uint64) error {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	if kept, removed := m.dedupeProperties(); len(removed) > 0 {
+		m.Class.Properties = kept
+		m.ClassVersion = v
+	}
+	return nil
+}
+
+// Property returns a deep copy of the property name, compared case-insensitively,
+// and whether the property exists
+func (m *metaClass) Property(name string) (*models.Property, bool) {
+	m.RLock()
+	defer m.RUnlock()
+
+	for _, p := range m.Class.Properties {
+		if p == nil || !strings.EqualFold(p.Name, name) {
+			continue
+		}
+		return copyProperty(p), true
+	}
+	return nil, false
+}
+
+// copyProperty returns a deep copy of p including its nested properties and module config
+func copyProperty(p *models.Property) *models.Property {
+	cp := *p
+	cp.DataType = slices.Clone(p.DataType)
+	cp.IndexFilterable = copyBool(p.IndexFilterable)
+	cp.IndexInverted = copyBool(p.IndexInverted)
+	cp.IndexRangeFilters = copyBool(p.IndexRangeFilters)
+	cp.IndexSearchable = copyBool(p.IndexSearchable)
+	cp.ModuleConfig = copyConfigValue(p.ModuleConfig)
+	cp.NestedProperties = copyNestedProperties(p.NestedProperties)
+	return &cp
+}
+
+func copyNestedProperties(props []*models.NestedProperty) []*models.NestedProperty {
+	if props == nil {
+		return nil
+	}
+	cps := make([]*models.NestedProperty, len(props))
+	for i, p := range props {
+		if p == nil {
+			continue
+		}
+		cp := *p
+		cp.DataType = slices.Clone(p.DataType)
+		cp.IndexFilterable = copyBool(p.IndexFilterable)
+		cp.IndexRangeFilters = copyBool(p.IndexRangeFilters)
+		cp.IndexSearchable = copyBool(p.IndexSearchable)
+		cp.NestedProperties = copyNestedProperties(p.NestedProperties)
+		cps[i] = &cp
+	}
+	return cps
+}
+
+func copyBool(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	v := *b
+	return &v
+}
+
+// copyConfigValue deep copies the maps and slices of a decoded JSON config value.
+// Any other value is immutable or opaque and returned as is.
+func copyConfigValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		cp := make(map[string]interface{}, len(v))
+		for k, x := range v {
+			cp[k] = copyConfigValue(x)
+		}
+		return cp
+	case []interface{}:
+		cp := make([]interface{}, len(v))
+		for i, x := range v {
+			cp[i] = copyConfigValue(x)
+		}
+		return cp
+	case []string:
+		return slices.Clone(v)
+	default:
+		return v
+	}
+}
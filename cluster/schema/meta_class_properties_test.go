@@ -0,0 +1,187 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/weaviate/entities/models"
+)
+
+func TestMetaClassMaxPropertyCount(t *testing.T) {
+	m := &metaClass{Class: models.Class{Class: "C", Properties: []*models.Property{
+		{Name: "a", DataType: []string{"int"}},
+		{Name: "b", DataType: []string{"int"}},
+	}}}
+
+	assert.NotNil(t, m.SetMaxPropertyCount(-1, 1))
+	assert.NotNil(t, m.SetMaxPropertyCount(1, 1))
+	require.Nil(t, m.SetMaxPropertyCount(3, 1))
+
+	// existing properties don't count as new ones
+	require.Nil(t, m.AddProperty(1,
+		&models.Property{Name: "A", DataType: []string{"int"}},
+		&models.Property{Name: "c", DataType: []string{"int"}}))
+	assert.Len(t, m.Class.Properties, 3)
+
+	err := m.AddProperty(2, &models.Property{Name: "d", DataType: []string{"int"}})
+	assert.ErrorIs(t, err, ErrTooManyProperties)
+	assert.ErrorContains(t, err, "3")
+	assert.Len(t, m.Class.Properties, 3)
+	assert.Equal(t, uint64(1), m.ClassVersion)
+
+	// removing the limit
+	require.Nil(t, m.SetMaxPropertyCount(0, 3))
+	require.Nil(t, m.AddProperty(4, &models.Property{Name: "d", DataType: []string{"int"}}))
+}
+
+func TestMetaClassPropertiesAddedAfter(t *testing.T) {
+	m := &metaClass{Class: models.Class{Class: "C", Properties: []*models.Property{
+		{Name: "a", DataType: []string{"int"}},
+	}}}
+	assert.Empty(t, m.PropertiesAddedAfter(0))
+
+	require.Nil(t, m.AddProperty(5, &models.Property{Name: "b", DataType: []string{"int"}}))
+	require.Nil(t, m.AddProperty(9,
+		&models.Property{Name: "A", DataType: []string{"int"}},
+		&models.Property{Name: "c", DataType: []string{"int"}}))
+
+	assert.Equal(t, []string{"b", "c"}, m.PropertiesAddedAfter(0))
+	assert.Equal(t, []string{"c"}, m.PropertiesAddedAfter(5))
+	assert.Empty(t, m.PropertiesAddedAfter(9))
+
+	// versions survive a snapshot
+	data, err := json.Marshal(m)
+	require.Nil(t, err)
+	restored := &metaClass{}
+	require.Nil(t, json.Unmarshal(data, restored))
+	assert.Equal(t, []string{"c"}, restored.PropertiesAddedAfter(5))
+}
+
+func TestMetaClassIsBreakingPropertyChange(t *testing.T) {
+	m := &metaClass{Class: models.Class{Class: "C", Properties: []*models.Property{
+		{Name: "title", DataType: []string{"text"}, Tokenization: "word"},
+	}}}
+
+	tests := []struct {
+		name     string
+		prop     models.Property
+		breaking bool
+	}{
+		{"unchanged", models.Property{Name: "title", DataType: []string{"text"}, Tokenization: "word"}, false},
+		{"description only", models.Property{Name: "title", DataType: []string{"text"}, Tokenization: "word", Description: "d"}, false},
+		{"data type", models.Property{Name: "title", DataType: []string{"text[]"}, Tokenization: "word"}, true},
+		{"tokenization", models.Property{Name: "title", DataType: []string{"text"}, Tokenization: "field"}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			breaking, err := m.IsBreakingPropertyChange("Title", tc.prop)
+			require.Nil(t, err)
+			assert.Equal(t, tc.breaking, breaking)
+		})
+	}
+
+	_, err := m.IsBreakingPropertyChange("missing", models.Property{})
+	assert.NotNil(t, err)
+}
+
+func TestMetaClassPropertyNames(t *testing.T) {
+	m := &metaClass{Class: models.Class{Class: "C"}}
+	assert.Empty(t, m.PropertyNames())
+
+	m.Class.Properties = []*models.Property{{Name: "title"}, {Name: "author"}, {Name: "Body"}}
+	assert.Equal(t, []string{"Body", "author", "title"}, m.PropertyNames())
+}
+
+func TestMetaClassConflictingDuplicateProperties(t *testing.T) {
+	m := &metaClass{Class: models.Class{Class: "C"}}
+	assert.Empty(t, m.ConflictingDuplicateProperties())
+
+	m.Class.Properties = []*models.Property{
+		{Name: "title", DataType: []string{"text"}},
+		{Name: "author"},
+		{Name: "Title", DataType: []string{"int"}},
+		{Name: "body"},
+		{Name: "title"},
+		{Name: "body"},
+	}
+	assert.Equal(t, map[string]int{"title": 3, "body": 2}, m.ConflictingDuplicateProperties())
+}
+
+func TestMetaClassDedupeProperties(t *testing.T) {
+	props := []*models.Property{
+		{Name: "title", DataType: []string{"text"}},
+		{Name: "author"},
+		{Name: "Title", DataType: []string{"int"}},
+		{Name: "body"},
+		{Name: "body"},
+	}
+	m := &metaClass{Class: models.Class{Class: "C", Properties: props}}
+
+	assert.Equal(t, []string{"Title", "body"}, m.DuplicateProperties())
+	assert.Len(t, m.Class.Properties, 5, "planning is read-only")
+
+	require.Nil(t, m.DedupeProperties(2))
+	assert.Equal(t, []*models.Property{props[0], props[1], props[3]}, m.Class.Properties)
+	assert.Equal(t, "Title", props[2].Name)
+	assert.Len(t, props, 5)
+	assert.Equal(t, uint64(2), m.ClassVersion)
+
+	require.Nil(t, m.DedupeProperties(3))
+	assert.Empty(t, m.DuplicateProperties())
+	assert.Len(t, m.Class.Properties, 3)
+	assert.Equal(t, uint64(2), m.ClassVersion, "nothing changed")
+}
+
+func TestMetaClassProperty(t *testing.T) {
+	filterable := true
+	m := &metaClass{Class: models.Class{Class: "C", Properties: []*models.Property{
+		{Name: "title", DataType: []string{"text"}, IndexFilterable: &filterable},
+		{
+			Name:             "author",
+			DataType:         []string{"object"},
+			NestedProperties: []*models.NestedProperty{{Name: "name", DataType: []string{"text"}}},
+		},
+	}}}
+
+	_, ok := m.Property("body")
+	assert.False(t, ok)
+
+	p, ok := m.Property("Title")
+	require.True(t, ok)
+	assert.Equal(t, m.Class.Properties[0], p)
+	*p.IndexFilterable = false
+	p.DataType[0] = "int"
+	assert.True(t, *m.Class.Properties[0].IndexFilterable)
+	assert.Equal(t, []string{"text"}, m.Class.Properties[0].DataType)
+
+	p, ok = m.Property("author")
+	require.True(t, ok)
+	p.NestedProperties[0].Name = "changed"
+	assert.Equal(t, "name", m.Class.Properties[1].NestedProperties[0].Name)
+
+	m.Class.Properties[0].ModuleConfig = map[string]interface{}{
+		"text2vec-contextionary": map[string]interface{}{"skip": true, "names": []interface{}{"a"}},
+	}
+	p, ok = m.Property("title")
+	require.True(t, ok)
+	assert.Equal(t, m.Class.Properties[0].ModuleConfig, p.ModuleConfig)
+	cfg := p.ModuleConfig.(map[string]interface{})["text2vec-contextionary"].(map[string]interface{})
+	cfg["skip"] = false
+	cfg["names"].([]interface{})[0] = "b"
+	assert.Equal(t, map[string]interface{}{
+		"text2vec-contextionary": map[string]interface{}{"skip": true, "names": []interface{}{"a"}},
+	}, m.Class.Properties[0].ModuleConfig)
+}
@@ -0,0 +1,477 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spaolacci/murmur3"
+	schemaConfig "github.com/weaviate/weaviate/entities/schema/config"
+	"golang.org/x/exp/slices"
+)
+
+// HottestNode returns the node which is the primary owner of the most shards and the number of those shards.
+// Ties are broken by choosing the lexicographically smallest node name.
+func (m *metaClass) HottestNode() (node string, count int) {
+	m.RLock()
+	defer m.RUnlock()
+
+	counts := make(map[string]int)
+	for _, p := range m.Sharding.Physical {
+		if len(p.BelongsToNodes) > 0 && p.BelongsToNodes[0] != "" {
+			counts[p.BelongsToNodes[0]]++
+		}
+	}
+	for n, c := range counts {
+		if c > count || (c == count && n < node) {
+			node, count = n, c
+		}
+	}
+	return node, count
+}
+
+// TenantsServableBy returns the sorted names of the tenants owned by at least one node of nodes
+func (m *metaClass) TenantsServableBy(nodes map[string]bool) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if slices.ContainsFunc(p.BelongsToNodes, func(node string) bool { return nodes[node] }) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// MinimalCoveringNodes returns the sorted names of a small set of nodes which together own every shard.
+// Nodes are chosen greedily by the number of shards they own which aren't covered yet, ties are broken
+// by choosing the lexicographically smallest node name. Shards without any owner are ignored.
+func (m *metaClass) MinimalCoveringNodes() []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	uncovered := make(map[string][]string, len(m.Sharding.Physical))
+	for name, p := range m.Sharding.Physical {
+		var nodes []string
+		for _, node := range p.BelongsToNodes {
+			if node != "" && !slices.Contains(nodes, node) {
+				nodes = append(nodes, node)
+			}
+		}
+		if len(nodes) > 0 {
+			uncovered[name] = nodes
+		}
+	}
+
+	res := make([]string, 0)
+	for len(uncovered) > 0 {
+		counts := make(map[string]int)
+		for _, nodes := range uncovered {
+			for _, node := range nodes {
+				counts[node]++
+			}
+		}
+		best, bestCount := "", 0
+		for node, n := range counts {
+			if n > bestCount || (n == bestCount && node < best) {
+				best, bestCount = node, n
+			}
+		}
+		for name, nodes := range uncovered {
+			if slices.Contains(nodes, best) {
+				delete(uncovered, name)
+			}
+		}
+		res = append(res, best)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// ShardsWhere returns the sorted names of the shards whose nodes satisfy pred.
+// pred receives a copy of the nodes of each shard.
+func (m *metaClass) ShardsWhere(pred func(nodes []string) bool) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if pred(slices.Clone(p.BelongsToNodes)) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// MaxFanoutTenant returns the tenant owned by the most distinct nodes and the number of those nodes.
+// Ties are broken by choosing the lexicographically smallest tenant name.
+func (m *metaClass) MaxFanoutTenant() (tenant string, fanout int) {
+	if m == nil {
+		return "", 0
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+
+	for name, p := range m.Sharding.Physical {
+		nodes := make(map[string]struct{}, len(p.BelongsToNodes))
+		for _, node := range p.BelongsToNodes {
+			if node != "" {
+				nodes[node] = struct{}{}
+			}
+		}
+		if n := len(nodes); n > fanout || (n == fanout && n > 0 && name < tenant) {
+			tenant, fanout = name, n
+		}
+	}
+	return tenant, fanout
+}
+
+// TenantsNotOwnedBy returns the sorted names of the shards which are not assigned to node
+func (m *metaClass) TenantsNotOwnedBy(node string) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0, len(m.Sharding.Physical))
+	for name, p := range m.Sharding.Physical {
+		if !slices.Contains(p.BelongsToNodes, node) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// PlacementDrift compares the owners of the tenants in expected with their expected owners.
+// It returns the tenants whose sorted owners differ from the sorted expected owners, mapped to
+// their actual and expected owners. Tenants which don't exist have no actual owners.
+func (m *metaClass) PlacementDrift(expected map[string][]string) map[string][2][]string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make(map[string][2][]string)
+	for tenant, want := range expected {
+		want = slices.Clone(want)
+		sort.Strings(want)
+		var got []string
+		if p, ok := m.Sharding.Physical[m.tenantName(tenant)]; ok {
+			got = slices.Clone(p.BelongsToNodes)
+			sort.Strings(got)
+		}
+		if !slices.Equal(got, want) {
+			res[tenant] = [2][]string{got, want}
+		}
+	}
+	return res
+}
+
+// StickyOwner returns the replica of shard which serves the client identified by clientKey.
+// It uses rendezvous hashing, so the same client always gets the same replica regardless of
+// the order of the replicas, while different clients are spread across the replicas.
+func (m *metaClass) StickyOwner(shard, clientKey string) (string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	p, ok := m.Sharding.Physical[m.tenantName(shard)]
+	if !ok {
+		return "", ErrShardNotFound
+	}
+	var owner string
+	var maxScore uint64
+	for _, node := range p.BelongsToNodes {
+		if node == "" {
+			continue
+		}
+		score := murmur3.Sum64([]byte(clientKey + "\x00" + node))
+		if owner == "" || score > maxScore || (score == maxScore && node < owner) {
+			owner, maxScore = node, score
+		}
+	}
+	if owner == "" {
+		return "", ErrShardOwnerNotFound
+	}
+	return owner, nil
+}
+
+// PlacementRows returns one row per tenant sorted by tenant name.
+// A row consists of the tenant name, its activity status, its primary owner and its other owners
+// joined by ";", so rows can be written as CSV as is.
+func (m *metaClass) PlacementRows() [][]string {
+	m.RLock()
+	defer m.RUnlock()
+
+	rows := make([][]string, 0, len(m.Sharding.Physical))
+	for name, p := range m.Sharding.Physical {
+		var primary, replicas string
+		if len(p.BelongsToNodes) > 0 {
+			primary = p.BelongsToNodes[0]
+			replicas = strings.Join(p.BelongsToNodes[1:], ";")
+		}
+		rows = append(rows, []string{name, p.ActivityStatus(), primary, replicas})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+	return rows
+}
+
+// CoTenants returns the sorted names of the other tenants owned by at least one of the nodes of tenant
+func (m *metaClass) CoTenants(tenant string) ([]string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	name := m.tenantName(tenant)
+	p, ok := m.Sharding.Physical[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrShardNotFound, tenant)
+	}
+	nodes := make(map[string]struct{}, len(p.BelongsToNodes))
+	for _, node := range p.BelongsToNodes {
+		if node != "" {
+			nodes[node] = struct{}{}
+		}
+	}
+
+	res := make([]string, 0)
+	for other, op := range m.Sharding.Physical {
+		if other == name {
+			continue
+		}
+		if slices.ContainsFunc(op.BelongsToNodes, func(node string) bool {
+			_, ok := nodes[node]
+			return ok
+		}) {
+			res = append(res, other)
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// CoLocatedShards groups the shards which are placed on exactly the same set of nodes.
+// The groups are keyed by the sorted nodes joined by "," and contain the sorted shard names.
+// Only node sets shared by at least two shards are returned.
+func (m *metaClass) CoLocatedShards() map[string][]string {
+	m.RLock()
+	defer m.RUnlock()
+
+	groups := make(map[string][]string)
+	for name, p := range m.Sharding.Physical {
+		if len(p.BelongsToNodes) == 0 {
+			continue
+		}
+		nodes := slices.Clone(p.BelongsToNodes)
+		sort.Strings(nodes)
+		key := strings.Join(slices.Compact(nodes), ",")
+		groups[key] = append(groups[key], name)
+	}
+	for key, shards := range groups {
+		if len(shards) < 2 {
+			delete(groups, key)
+			continue
+		}
+		sort.Strings(shards)
+	}
+	return groups
+}
+
+// NodeRole counts the shards a node owns as primary and as replica
+type NodeRole struct {
+	PrimaryCount int
+	ReplicaCount int
+}
+
+// NodeRoles returns for every node owning at least one shard how many shards it owns as primary and as replica
+func (m *metaClass) NodeRoles() map[string]NodeRole {
+	m.RLock()
+	defer m.RUnlock()
+	return m.nodeRoles()
+}
+
+func (m *metaClass) nodeRoles() map[string]NodeRole {
+	res := make(map[string]NodeRole)
+	for _, p := range m.Sharding.Physical {
+		for i, node := range p.BelongsToNodes {
+			if node == "" {
+				continue
+			}
+			r := res[node]
+			if i == 0 {
+				r.PrimaryCount++
+			} else {
+				r.ReplicaCount++
+			}
+			res[node] = r
+		}
+	}
+	return res
+}
+
+// primaryLoadWeight is the weight of a primary shard relative to a replica in NodeLoad.Weighted,
+// primaries serve the writes of their shard in addition to reads
+const primaryLoadWeight = 2
+
+// NodeLoad counts the shards a node owns as primary and as replica
+type NodeLoad struct {
+	PrimaryCount int
+	ReplicaCount int
+	// Weighted is the load of the node with primaries weighted by primaryLoadWeight
+	Weighted int
+}
+
+// NodeLoadBreakdown returns for every node owning at least one shard its primary and replica
+// counts and its weighted load
+func (m *metaClass) NodeLoadBreakdown() map[string]NodeLoad {
+	m.RLock()
+	defer m.RUnlock()
+
+	roles := m.nodeRoles()
+	res := make(map[string]NodeLoad, len(roles))
+	for node, r := range roles {
+		res[node] = NodeLoad{
+			PrimaryCount: r.PrimaryCount,
+			ReplicaCount: r.ReplicaCount,
+			Weighted:     primaryLoadWeight*r.PrimaryCount + r.ReplicaCount,
+		}
+	}
+	return res
+}
+
+// NodeStatusCounts returns the number of shards owned by node per activity status
+func (m *metaClass) NodeStatusCounts(node string) map[string]int {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make(map[string]int)
+	for _, p := range m.Sharding.Physical {
+		if slices.Contains(p.BelongsToNodes, node) {
+			res[p.ActivityStatus()]++
+		}
+	}
+	return res
+}
+
+// VectorIndexSummary describes a single vector index
+type VectorIndexSummary struct {
+	// Name of the named vector, empty for the class level vector index
+	Name      string
+	IndexType string
+	Distance  string
+}
+
+// VectorSummary summarizes the vector configuration of a class
+type VectorSummary struct {
+	// IndexType and Distance of the class level vector index, if any
+	IndexType string
+	Distance  string
+	// NamedVectors is true if the class configures named vectors
+	NamedVectors bool
+	// Vectors lists the named vector indexes sorted by name
+	Vectors []VectorIndexSummary
+}
+
+// VectorConfigSummary returns the vector index types and distance metrics of the class
+func (m *metaClass) VectorConfigSummary() VectorSummary {
+	m.RLock()
+	defer m.RUnlock()
+
+	vs := VectorSummary{
+		IndexType:    m.Class.VectorIndexType,
+		Distance:     vectorIndexDistance(m.Class.VectorIndexConfig),
+		NamedVectors: len(m.Class.VectorConfig) > 0,
+	}
+	for name, cfg := range m.Class.VectorConfig {
+		vs.Vectors = append(vs.Vectors, VectorIndexSummary{
+			Name:      name,
+			IndexType: cfg.VectorIndexType,
+			Distance:  vectorIndexDistance(cfg.VectorIndexConfig),
+		})
+	}
+	sort.Slice(vs.Vectors, func(i, j int) bool { return vs.Vectors[i].Name < vs.Vectors[j].Name })
+	return vs
+}
+
+// vectorIndexDistance returns the distance metric of either a parsed or a raw vector index config
+func vectorIndexDistance(cfg interface{}) string {
+	switch c := cfg.(type) {
+	case schemaConfig.VectorIndexConfig:
+		return c.DistanceName()
+	case map[string]interface{}:
+		if d, ok := c["distance"].(string); ok {
+			return d
+		}
+	}
+	return ""
+}
+
+// ShardingFingerprint returns a hash of the name, status and nodes of all shards.
+// Shards and nodes are hashed in sorted order, so equal sharding states produce
+// the same fingerprint independently of map iteration order and node order.
+func (m *metaClass) ShardingFingerprint() uint64 {
+	m.RLock()
+	defer m.RUnlock()
+	return m.shardingFingerprint()
+}
+
+func (m *metaClass) shardingFingerprint() uint64 {
+	names := make([]string, 0, len(m.Sharding.Physical))
+	for name := range m.Sharding.Physical {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := murmur3.New64()
+	for _, name := range names {
+		p := m.Sharding.Physical[name]
+		nodes := slices.Clone(p.BelongsToNodes)
+		sort.Strings(nodes)
+		h.Write([]byte(name + "\x00" + p.Status + "\x00" + strings.Join(nodes, "\x00") + "\x01"))
+	}
+	return h.Sum64()
+}
+
+// ClassDiagnostics is a consistent summary of the class and its sharding state
+type ClassDiagnostics struct {
+	// SchemaHash is a hash of the JSON representation of the class
+	SchemaHash          uint64         `json:"schemaHash"`
+	Tenants             int            `json:"tenants"`
+	StatusCounts        map[string]int `json:"statusCounts"`
+	ShardingFingerprint uint64         `json:"shardingFingerprint"`
+	ClassVersion        uint64         `json:"classVersion"`
+	ShardVersion        uint64         `json:"shardVersion"`
+}
+
+// Diagnostics returns the hash of the class, the number of tenants, the number of tenants
+// per activity status and the sharding fingerprint, all read under the same lock.
+func (m *metaClass) Diagnostics() ClassDiagnostics {
+	m.RLock()
+	defer m.RUnlock()
+
+	d := ClassDiagnostics{
+		Tenants:             len(m.Sharding.Physical),
+		StatusCounts:        make(map[string]int),
+		ShardingFingerprint: m.shardingFingerprint(),
+		ClassVersion:        m.ClassVersion,
+		ShardVersion:        m.ShardVersion,
+	}
+	if b, err := json.Marshal(&m.Class); err == nil {
+		d.SchemaHash = murmur3.Sum64(b)
+	}
+	for _, p := range m.Sharding.Physical {
+		d.StatusCounts[p.ActivityStatus()]++
+	}
+	return d
+}
@@ -0,0 +1,441 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/entities/vectorindex/hnsw"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassHottestNode(t *testing.T) {
+	m := &metaClass{}
+	node, count := m.HottestNode()
+	assert.Equal(t, "", node)
+	assert.Equal(t, 0, count)
+
+	m.Sharding.Physical = map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"B", "A"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"A"}},
+		"S3": {Name: "S3", BelongsToNodes: []string{"B"}},
+		"S4": {Name: "S4", BelongsToNodes: []string{"A", "B"}},
+		"S5": {Name: "S5", BelongsToNodes: []string{"C"}},
+	}
+	node, count = m.HottestNode()
+	assert.Equal(t, "A", node)
+	assert.Equal(t, 2, count)
+}
+
+func TestMetaClassTenantsServableBy(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}},
+		"T2": {Name: "T2", BelongsToNodes: []string{"C"}},
+		"T3": {Name: "T3", BelongsToNodes: []string{"D", "B"}},
+		"T4": {Name: "T4"},
+	}}}
+	assert.Empty(t, m.TenantsServableBy(nil))
+	assert.Equal(t, []string{"T1", "T3"}, m.TenantsServableBy(map[string]bool{"B": true, "C": false}))
+	assert.Equal(t, []string{"T1", "T2"}, m.TenantsServableBy(map[string]bool{"A": true, "C": true}))
+}
+
+func TestMetaClassMinimalCoveringNodes(t *testing.T) {
+	m := &metaClass{}
+	assert.Empty(t, m.MinimalCoveringNodes())
+
+	m.Sharding = sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A", "B"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"B", "C"}},
+		"S3": {Name: "S3", BelongsToNodes: []string{"C", "B"}},
+		"S4": {Name: "S4", BelongsToNodes: []string{"D", "E", "D"}},
+		"S5": {Name: "S5", BelongsToNodes: []string{""}},
+		"S6": {Name: "S6"},
+	}}
+	assert.Equal(t, []string{"B", "D"}, m.MinimalCoveringNodes())
+}
+
+func TestMetaClassShardsWhere(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A", "B"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"B"}},
+		"S3": {Name: "S3", BelongsToNodes: []string{"C", "B"}},
+	}}}
+	res := m.ShardsWhere(func(nodes []string) bool {
+		ok := len(nodes) > 0 && nodes[len(nodes)-1] == "B"
+		if len(nodes) > 0 {
+			nodes[0] = "X"
+		}
+		return ok
+	})
+	assert.Equal(t, []string{"S1", "S2", "S3"}, res)
+	assert.Equal(t, []string{"A", "B"}, m.Sharding.Physical["S1"].BelongsToNodes)
+	assert.Empty(t, m.ShardsWhere(func([]string) bool { return false }))
+}
+
+func TestMetaClassMaxFanoutTenant(t *testing.T) {
+	var m *metaClass
+	tenant, fanout := m.MaxFanoutTenant()
+	assert.Equal(t, "", tenant)
+	assert.Equal(t, 0, fanout)
+
+	m = &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A", "B", "A"}},
+		"T3": {Name: "T3", BelongsToNodes: []string{"C", "B"}},
+		"T4": {Name: "T4"},
+	}}}
+	tenant, fanout = m.MaxFanoutTenant()
+	assert.Equal(t, "T2", tenant)
+	assert.Equal(t, 2, fanout)
+}
+
+func TestMetaClassShardReplicasExcludingPrimary(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A", "B", "C"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"A"}},
+	}}}
+
+	_, err := m.ShardReplicasExcludingPrimary("X")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+
+	replicas, err := m.ShardReplicasExcludingPrimary("S2")
+	require.Nil(t, err)
+	assert.Equal(t, []string{}, replicas)
+
+	replicas, err = m.ShardReplicasExcludingPrimary("S1")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"B", "C"}, replicas)
+	replicas[0] = "X"
+	assert.Equal(t, []string{"A", "B", "C"}, m.Sharding.Physical["S1"].BelongsToNodes)
+}
+
+func TestMetaClassTenantsNotOwnedBy(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}},
+		"T2": {Name: "T2", BelongsToNodes: []string{"C"}},
+		"T3": {Name: "T3", BelongsToNodes: []string{"B"}},
+		"T4": {Name: "T4"},
+	}}}
+	assert.Equal(t, []string{"T2", "T3", "T4"}, m.TenantsNotOwnedBy("A"))
+	assert.Equal(t, []string{}, (&metaClass{}).TenantsNotOwnedBy("A"))
+}
+
+func TestMetaClassCoTenants(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}},
+		"T2": {Name: "T2", BelongsToNodes: []string{"B", "C"}},
+		"T3": {Name: "T3", BelongsToNodes: []string{"C"}},
+		"T4": {Name: "T4", BelongsToNodes: []string{"A"}},
+		"T5": {Name: "T5"},
+	}}}
+
+	tenants, err := m.CoTenants("T1")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"T2", "T4"}, tenants)
+	tenants, err = m.CoTenants("T5")
+	require.Nil(t, err)
+	assert.Empty(t, tenants)
+	_, err = m.CoTenants("T6")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+}
+
+func TestMetaClassCoLocatedShards(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A", "B"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"B", "A"}},
+		"S3": {Name: "S3", BelongsToNodes: []string{"A", "C"}},
+		"S4": {Name: "S4", BelongsToNodes: []string{"C"}},
+		"S5": {Name: "S5", BelongsToNodes: []string{"C", "C"}},
+		"S6": {Name: "S6"},
+		"S0": {Name: "S0", BelongsToNodes: []string{"A", "B"}},
+	}}}
+	assert.Equal(t, map[string][]string{
+		"A,B": {"S0", "S1", "S2"},
+		"C":   {"S4", "S5"},
+	}, m.CoLocatedShards())
+}
+
+func TestMetaClassNodeRoles(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A", "B", "C"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"A", "B"}},
+		"S3": {Name: "S3", BelongsToNodes: []string{"B"}},
+	}}}
+	assert.Equal(t, map[string]NodeRole{
+		"A": {PrimaryCount: 2},
+		"B": {PrimaryCount: 1, ReplicaCount: 2},
+		"C": {ReplicaCount: 1},
+	}, m.NodeRoles())
+}
+
+func TestMetaClassNodeLoadBreakdown(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A", "B", "C"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"A", "B"}},
+		"S3": {Name: "S3", BelongsToNodes: []string{"B"}},
+	}}}
+	assert.Equal(t, map[string]NodeLoad{
+		"A": {PrimaryCount: 2, Weighted: 4},
+		"B": {PrimaryCount: 1, ReplicaCount: 2, Weighted: 4},
+		"C": {ReplicaCount: 1, Weighted: 1},
+	}, m.NodeLoadBreakdown())
+}
+
+func TestMetaClassNodeStatusCounts(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A", "B"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"B"}, Status: models.TenantActivityStatusCOLD},
+		"S3": {Name: "S3", BelongsToNodes: []string{"C", "A"}, Status: models.TenantActivityStatusCOLD},
+		"S4": {Name: "S4", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusFROZEN},
+	}}}
+	assert.Equal(t, map[string]int{
+		models.TenantActivityStatusHOT:    1,
+		models.TenantActivityStatusCOLD:   1,
+		models.TenantActivityStatusFROZEN: 1,
+	}, m.NodeStatusCounts("A"))
+	assert.Empty(t, m.NodeStatusCounts("D"))
+}
+
+func TestMetaClassVectorConfigSummary(t *testing.T) {
+	m := &metaClass{Class: models.Class{
+		VectorIndexType:   "hnsw",
+		VectorIndexConfig: hnsw.UserConfig{Distance: "cosine"},
+	}}
+	assert.Equal(t, VectorSummary{IndexType: "hnsw", Distance: "cosine"}, m.VectorConfigSummary())
+
+	m = &metaClass{Class: models.Class{VectorConfig: map[string]models.VectorConfig{
+		"title": {VectorIndexType: "flat", VectorIndexConfig: map[string]interface{}{"distance": "dot"}},
+		"body":  {VectorIndexType: "hnsw", VectorIndexConfig: hnsw.UserConfig{Distance: "l2-squared"}},
+	}}}
+	assert.Equal(t, VectorSummary{
+		NamedVectors: true,
+		Vectors: []VectorIndexSummary{
+			{Name: "body", IndexType: "hnsw", Distance: "l2-squared"},
+			{Name: "title", IndexType: "flat", Distance: "dot"},
+		},
+	}, m.VectorConfigSummary())
+}
+
+func TestMetaClassTenantStatusAndOwner(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusCOLD},
+		"T2": {Name: "T2", BelongsToNodes: []string{"B"}},
+		"T3": {Name: "T3"},
+	}}}
+
+	status, owner, err := m.TenantStatusAndOwner("T1")
+	require.Nil(t, err)
+	assert.Equal(t, models.TenantActivityStatusCOLD, status)
+	assert.Equal(t, "A", owner)
+
+	status, owner, err = m.TenantStatusAndOwner("T2")
+	require.Nil(t, err)
+	assert.Equal(t, models.TenantActivityStatusHOT, status)
+	assert.Equal(t, "B", owner)
+
+	_, _, err = m.TenantStatusAndOwner("T3")
+	assert.ErrorIs(t, err, ErrShardOwnerNotFound)
+	_, _, err = m.TenantStatusAndOwner("X")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+}
+
+func TestMetaClassSnapshot(t *testing.T) {
+	m := &metaClass{
+		Class: models.Class{Class: "C"},
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A"}},
+		}},
+	}
+	cls, st := m.Snapshot()
+	assert.Equal(t, "C", cls.Class)
+	assert.Equal(t, m.Sharding, *st)
+
+	st.Physical["S1"].BelongsToNodes[0] = "B"
+	cls.Class = "D"
+	assert.Equal(t, "A", m.Sharding.Physical["S1"].BelongsToNodes[0])
+	assert.Equal(t, "C", m.Class.Class)
+}
+
+func TestMetaClassPlacementDrift(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"B", "A"}},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A", "C"}},
+		"T3": {Name: "T3", BelongsToNodes: []string{"C"}},
+	}}}
+	expected := map[string][]string{
+		"T1": {"A", "B"},
+		"T2": {"B", "A"},
+		"T4": {"A"},
+	}
+	drift := m.PlacementDrift(expected)
+	assert.Equal(t, map[string][2][]string{
+		"T2": {{"A", "C"}, {"A", "B"}},
+		"T4": {nil, {"A"}},
+	}, drift)
+
+	// results and input are copies
+	drift["T2"][0][0] = "X"
+	assert.Equal(t, []string{"A", "C"}, m.Sharding.Physical["T2"].BelongsToNodes)
+	assert.Equal(t, []string{"B", "A"}, expected["T2"])
+}
+
+func TestMetaClassShardAndStatusFromUUID(t *testing.T) {
+	m := &metaClass{}
+	_, _, err := m.ShardAndStatusFromUUID([]byte("id"))
+	assert.ErrorIs(t, err, ErrShardNotFound)
+
+	m.Sharding = sharding.State{
+		Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusCOLD},
+		},
+		Virtual: []sharding.Virtual{{Name: "V1", Upper: math.MaxUint64, AssignedToPhysical: "S1"}},
+	}
+	shard, status, err := m.ShardAndStatusFromUUID([]byte("id"))
+	require.Nil(t, err)
+	assert.Equal(t, "S1", shard)
+	assert.Equal(t, models.TenantActivityStatusCOLD, status)
+
+	m.Sharding.Virtual[0].AssignedToPhysical = "S2"
+	_, _, err = m.ShardAndStatusFromUUID([]byte("id"))
+	assert.ErrorIs(t, err, ErrShardNotFound)
+
+	mt := &metaClass{
+		Class: models.Class{MultiTenancyConfig: &models.MultiTenancyConfig{Enabled: true}},
+		Sharding: sharding.State{
+			Physical:            map[string]sharding.Physical{"T1": {Name: "T1", Status: models.TenantActivityStatusHOT}},
+			PartitioningEnabled: true,
+		},
+	}
+	_, _, err = mt.ShardAndStatusFromUUID([]byte("id"))
+	assert.ErrorIs(t, err, ErrMultiTenancyNotSupported)
+}
+
+func TestMetaClassStickyOwner(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A", "B", "C"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"C", "A", "B"}},
+		"S3": {Name: "S3"},
+	}}}
+
+	_, err := m.StickyOwner("X", "client")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+	_, err = m.StickyOwner("S3", "client")
+	assert.ErrorIs(t, err, ErrShardOwnerNotFound)
+
+	owners := make(map[string]bool)
+	for i := 0; i < 32; i++ {
+		key := fmt.Sprintf("client-%d", i)
+		owner, err := m.StickyOwner("S1", key)
+		require.Nil(t, err)
+		again, _ := m.StickyOwner("S1", key)
+		assert.Equal(t, owner, again)
+		// independent of the node order
+		other, _ := m.StickyOwner("S2", key)
+		assert.Equal(t, owner, other)
+		owners[owner] = true
+	}
+	assert.Len(t, owners, 3)
+}
+
+func TestMetaClassPlacementRows(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T2": {Name: "T2", BelongsToNodes: []string{"B"}, Status: models.TenantActivityStatusCOLD},
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B", "C"}},
+		"T3": {Name: "T3", Status: models.TenantActivityStatusFROZEN},
+	}}}
+	assert.Equal(t, [][]string{
+		{"T1", models.TenantActivityStatusHOT, "A", "B;C"},
+		{"T2", models.TenantActivityStatusCOLD, "B", ""},
+		{"T3", models.TenantActivityStatusFROZEN, "", ""},
+	}, m.PlacementRows())
+}
+
+func TestMetaClassClassInfoJSON(t *testing.T) {
+	m := &metaClass{
+		Class: models.Class{
+			Class:              "C",
+			Properties:         []*models.Property{{Name: "p"}},
+			MultiTenancyConfig: &models.MultiTenancyConfig{Enabled: true},
+			ReplicationConfig:  &models.ReplicationConfig{Factor: 2},
+		},
+		ClassVersion: 3,
+		ShardVersion: 4,
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1"},
+			"T2": {Name: "T2"},
+		}},
+	}
+	b, err := m.ClassInfoJSON()
+	require.Nil(t, err)
+	assert.JSONEq(t, `{
+		"exists": true,
+		"properties": 1,
+		"tenants": 2,
+		"replicationFactor": 2,
+		"multiTenancy": {"enabled": true, "autoTenantCreation": false, "autoTenantActivation": false, "normalizeTenantNames": false},
+		"classVersion": 3,
+		"shardVersion": 4
+	}`, string(b))
+}
+
+func TestMetaClassShardingFingerprint(t *testing.T) {
+	newMetaClass := func() *metaClass {
+		return &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusHOT},
+			"T2": {Name: "T2", BelongsToNodes: []string{"C"}, Status: models.TenantActivityStatusCOLD},
+		}}}
+	}
+	m1, m2 := newMetaClass(), newMetaClass()
+	assert.Equal(t, m1.ShardingFingerprint(), m2.ShardingFingerprint())
+
+	m2.Sharding.Physical["T1"] = sharding.Physical{Name: "T1", BelongsToNodes: []string{"B", "A"}, Status: models.TenantActivityStatusHOT}
+	assert.Equal(t, m1.ShardingFingerprint(), m2.ShardingFingerprint())
+	assert.Equal(t, []string{"B", "A"}, m2.Sharding.Physical["T1"].BelongsToNodes)
+
+	m2.Sharding.Physical["T2"] = sharding.Physical{Name: "T2", BelongsToNodes: []string{"C"}, Status: models.TenantActivityStatusHOT}
+	assert.NotEqual(t, m1.ShardingFingerprint(), m2.ShardingFingerprint())
+
+	m2 = newMetaClass()
+	delete(m2.Sharding.Physical, "T2")
+	assert.NotEqual(t, m1.ShardingFingerprint(), m2.ShardingFingerprint())
+}
+
+func TestMetaClassDiagnostics(t *testing.T) {
+	m := &metaClass{
+		Class: models.Class{Class: "C"},
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+			"T2": {Name: "T2", BelongsToNodes: []string{"B"}, Status: models.TenantActivityStatusCOLD},
+			"T3": {Name: "T3", BelongsToNodes: []string{"B"}},
+		}},
+		ShardVersion: 2,
+	}
+	d := m.Diagnostics()
+	assert.Equal(t, 3, d.Tenants)
+	assert.Equal(t, map[string]int{models.TenantActivityStatusHOT: 2, models.TenantActivityStatusCOLD: 1}, d.StatusCounts)
+	assert.Equal(t, m.ShardingFingerprint(), d.ShardingFingerprint)
+	assert.Equal(t, uint64(2), d.ShardVersion)
+	assert.NotZero(t, d.SchemaHash)
+	assert.Equal(t, d, m.Diagnostics())
+
+	m.Class.Description = "changed"
+	assert.NotEqual(t, d.SchemaHash, m.Diagnostics().SchemaHash)
+
+	_, err := json.Marshal(d)
+	require.Nil(t, err)
+}
@@ -0,0 +1,248 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/weaviate/weaviate/cluster/types"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+	"golang.org/x/exp/slices"
+)
+
+// ShardMove moves the primary ownership of Shard from node From to node To
+type ShardMove struct {
+	Shard string
+	From  string
+	To    string
+}
+
+// ComputeRebalancePlan returns the moves needed to spread the primary ownership of all shards
+// evenly across nodes. Shards whose primary is not in nodes are always moved.
+// A shard is never moved to a node already owning one of its replicas.
+// The plan is deterministic and doesn't mutate the state.
+func (m *metaClass) ComputeRebalancePlan(nodes []string) []ShardMove {
+	m.RLock()
+	defer m.RUnlock()
+
+	nodes = slices.Clone(nodes)
+	sort.Strings(nodes)
+	nodes = slices.Compact(nodes)
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	shardNames := make([]string, 0, len(m.Sharding.Physical))
+	for name := range m.Sharding.Physical {
+		shardNames = append(shardNames, name)
+	}
+	sort.Strings(shardNames)
+
+	owned := make(map[string][]string, len(nodes))
+	for _, n := range nodes {
+		owned[n] = nil
+	}
+	var pool []string // shards which must get a new primary
+	for _, name := range shardNames {
+		p := m.Sharding.Physical[name]
+		primary := ""
+		if len(p.BelongsToNodes) > 0 {
+			primary = p.BelongsToNodes[0]
+		}
+		if _, ok := owned[primary]; ok {
+			owned[primary] = append(owned[primary], name)
+		} else {
+			pool = append(pool, name)
+		}
+	}
+
+	// nodes which own the most shards are given the extra slots to minimize the moves
+	byLoad := slices.Clone(nodes)
+	sort.SliceStable(byLoad, func(i, j int) bool { return len(owned[byLoad[i]]) > len(owned[byLoad[j]]) })
+	target := make(map[string]int, len(nodes))
+	for i, n := range byLoad {
+		target[n] = len(shardNames) / len(nodes)
+		if i < len(shardNames)%len(nodes) {
+			target[n]++
+		}
+	}
+
+	// collect the excess shards of over loaded nodes
+	for _, n := range nodes {
+		if excess := len(owned[n]) - target[n]; excess > 0 {
+			pool = append(pool, owned[n][len(owned[n])-excess:]...)
+		}
+	}
+	sort.Strings(pool)
+
+	var plan []ShardMove
+	for _, name := range pool {
+		p := m.Sharding.Physical[name]
+		to, deficit := "", 0
+		for _, n := range nodes {
+			d := target[n] - len(owned[n])
+			if d > deficit && !slices.Contains(p.BelongsToNodes, n) {
+				to, deficit = n, d
+			}
+		}
+		if to == "" {
+			continue // no eligible node, best effort
+		}
+		from := ""
+		if len(p.BelongsToNodes) > 0 {
+			from = p.BelongsToNodes[0]
+		}
+		owned[to] = append(owned[to], name)
+		plan = append(plan, ShardMove{Shard: name, From: from, To: to})
+	}
+	return plan
+}
+
+// ApplyRebalancePlan applies all moves of plan at once.
+// A move is valid if its source is still the primary of the shard and its target doesn't own a replica yet.
+// A move of a shard without owner must have an empty source; its target becomes the only owner.
+// If any move is invalid, nothing is applied and an error wrapping ErrRebalanceConflict is returned
+// so that the caller can compute a new plan.
+func (m *metaClass) ApplyRebalancePlan(plan []ShardMove, v uint64, modifiedAt time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	updated := make(map[string]sharding.Physical, len(plan))
+	for _, mv := range plan {
+		p, ok := updated[mv.Shard]
+		if !ok {
+			if p, ok = m.Sharding.Physical[mv.Shard]; !ok {
+				return fmt.Errorf("%w: shard %q: %w", ErrRebalanceConflict, mv.Shard, ErrShardNotFound)
+			}
+			p = p.DeepCopy()
+		}
+		if len(p.BelongsToNodes) == 0 {
+			if mv.From != "" {
+				return fmt.Errorf("%w: shard %q: node %q is not the primary anymore", ErrRebalanceConflict, mv.Shard, mv.From)
+			}
+			p.BelongsToNodes = []string{mv.To}
+			updated[mv.Shard] = p
+			continue
+		}
+		if p.BelongsToNodes[0] != mv.From {
+			return fmt.Errorf("%w: shard %q: node %q is not the primary anymore", ErrRebalanceConflict, mv.Shard, mv.From)
+		}
+		if slices.Contains(p.BelongsToNodes, mv.To) {
+			return fmt.Errorf("%w: shard %q: node %q already owns a replica", ErrRebalanceConflict, mv.Shard, mv.To)
+		}
+		p.BelongsToNodes[0] = mv.To
+		updated[mv.Shard] = p
+	}
+
+	names := make([]string, 0, len(updated))
+	for name := range updated {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m.setShard(name, updated[name], v, modifiedAt)
+	}
+	return nil
+}
+
+type DrainAction string
+
+const (
+	// DrainRelocate moves the replica of the drained node to Target
+	DrainRelocate DrainAction = "RELOCATE"
+	// DrainFreeze freezes the tenant because there is no node to relocate it to
+	DrainFreeze DrainAction = "FREEZE"
+)
+
+// DrainStep is the recommended action for a single tenant of a drained node
+type DrainStep struct {
+	Tenant string
+	Action DrainAction
+	// Target is the node to relocate to, empty for DrainFreeze
+	Target string
+}
+
+// DrainPlan lists the steps needed to move all tenants off Node, sorted by tenant
+type DrainPlan struct {
+	Node  string
+	Steps []DrainStep
+}
+
+// DrainPlan returns the plan to move every HOT or COLD tenant owned by node to one of candidateNodes.
+// Each tenant is relocated to the least loaded candidate not owning it yet, taking the loads of
+// nodeLoads and of the previous steps into account. Tenants without such candidate are frozen instead.
+// Frozen tenants don't need to be moved. The state is not mutated.
+func (m *metaClass) DrainPlan(node string, candidateNodes []string, nodeLoads map[string]int) DrainPlan {
+	m.RLock()
+	defer m.RUnlock()
+
+	candidates := make([]string, 0, len(candidateNodes))
+	for _, c := range candidateNodes {
+		if c != node && !slices.Contains(candidates, c) {
+			candidates = append(candidates, c)
+		}
+	}
+	sort.Strings(candidates)
+	loads := make(map[string]int, len(nodeLoads))
+	for n, load := range nodeLoads {
+		loads[n] = load
+	}
+
+	tenants := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if slices.Contains(p.BelongsToNodes, node) {
+			tenants = append(tenants, name)
+		}
+	}
+	sort.Strings(tenants)
+
+	plan := DrainPlan{Node: node, Steps: make([]DrainStep, 0, len(tenants))}
+	for _, name := range tenants {
+		p := m.Sharding.Physical[name]
+		switch p.ActivityStatus() {
+		case models.TenantActivityStatusFROZEN, types.TenantActivityStatusFREEZING:
+			continue
+		}
+		if targets := selectReplicaNodes(candidates, p.BelongsToNodes, 1, loads); len(targets) > 0 {
+			plan.Steps = append(plan.Steps, DrainStep{Tenant: name, Action: DrainRelocate, Target: targets[0]})
+		} else {
+			plan.Steps = append(plan.Steps, DrainStep{Tenant: name, Action: DrainFreeze})
+		}
+	}
+	return plan
+}
+
+// MigrationImpact returns the number of distinct shards moved by plan and the number of distinct
+// nodes moving or receiving them. Moves of unknown shards and moves to the same node are ignored.
+func (m *metaClass) MigrationImpact(plan []ShardMove) (shardsMoving int, nodesAffected int) {
+	m.RLock()
+	defer m.RUnlock()
+
+	shards := make(map[string]struct{}, len(plan))
+	nodes := make(map[string]struct{})
+	for _, mv := range plan {
+		if _, ok := m.Sharding.Physical[mv.Shard]; !ok || mv.From == mv.To {
+			continue
+		}
+		shards[mv.Shard] = struct{}{}
+		for _, node := range []string{mv.From, mv.To} {
+			if node != "" {
+				nodes[node] = struct{}{}
+			}
+		}
+	}
+	return len(shards), len(nodes)
+}
@@ -0,0 +1,144 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassComputeRebalancePlan(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"A"}},
+		"S3": {Name: "S3", BelongsToNodes: []string{"A"}},
+		"S4": {Name: "S4", BelongsToNodes: []string{"A", "B"}},
+		"S5": {Name: "S5", BelongsToNodes: []string{"B"}},
+		"S6": {Name: "S6", BelongsToNodes: []string{"X"}},
+	}}}
+
+	plan := m.ComputeRebalancePlan([]string{"C", "B", "A"})
+	assert.Equal(t, []ShardMove{
+		{Shard: "S3", From: "A", To: "C"},
+		{Shard: "S4", From: "A", To: "C"},
+		{Shard: "S6", From: "X", To: "B"},
+	}, plan)
+	assert.Equal(t, plan, m.ComputeRebalancePlan([]string{"A", "B", "C"}), "plan must be deterministic")
+
+	// only shards with a primary outside of the given nodes are moved
+	assert.Equal(t, []ShardMove{
+		{Shard: "S5", From: "B", To: "A"},
+		{Shard: "S6", From: "X", To: "A"},
+	}, m.ComputeRebalancePlan([]string{"A"}))
+	assert.Nil(t, m.ComputeRebalancePlan(nil))
+}
+
+func TestMetaClassApplyRebalancePlan(t *testing.T) {
+	newMetaClass := func() *metaClass {
+		return &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A", "B"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"A"}},
+		}}}
+	}
+
+	t.Run("valid plan", func(t *testing.T) {
+		m := newMetaClass()
+		plan := m.ComputeRebalancePlan([]string{"A", "C"})
+		require.Nil(t, m.ApplyRebalancePlan(plan, 2, time.UnixMilli(3)))
+		assert.Empty(t, m.ComputeRebalancePlan([]string{"A", "C"}))
+		assert.Equal(t, uint64(2), m.ShardVersion)
+		changes, _ := m.TenantChangesSince(0)
+		assert.Len(t, changes, len(plan))
+	})
+
+	t.Run("shard without owner", func(t *testing.T) {
+		m := newMetaClass()
+		m.Sharding.Physical["S3"] = sharding.Physical{Name: "S3"}
+		plan := m.ComputeRebalancePlan([]string{"A", "C"})
+		assert.Contains(t, plan, ShardMove{Shard: "S3", From: "", To: "C"})
+		require.Nil(t, m.ApplyRebalancePlan(plan, 1, time.Time{}))
+		assert.Equal(t, []string{"C"}, m.Sharding.Physical["S3"].BelongsToNodes)
+		assert.Empty(t, m.ComputeRebalancePlan([]string{"A", "C"}))
+
+		m.Sharding.Physical["S4"] = sharding.Physical{Name: "S4"}
+		err := m.ApplyRebalancePlan([]ShardMove{{Shard: "S4", From: "A", To: "C"}}, 1, time.Time{})
+		assert.ErrorIs(t, err, ErrRebalanceConflict)
+	})
+
+	tests := []struct {
+		name string
+		plan []ShardMove
+	}{
+		{"unknown shard", []ShardMove{{Shard: "S2", From: "A", To: "C"}, {Shard: "X", From: "A", To: "C"}}},
+		{"stale source", []ShardMove{{Shard: "S2", From: "A", To: "C"}, {Shard: "S1", From: "B", To: "C"}}},
+		{"target is replica", []ShardMove{{Shard: "S2", From: "A", To: "C"}, {Shard: "S1", From: "A", To: "B"}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMetaClass()
+			assert.ErrorIs(t, m.ApplyRebalancePlan(tc.plan, 1, time.Time{}), ErrRebalanceConflict)
+			assert.Equal(t, newMetaClass().Sharding, m.Sharding, "nothing is applied")
+		})
+	}
+}
+
+func TestMetaClassDrainPlan(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusCOLD},
+		"T3": {Name: "T3", BelongsToNodes: []string{"B", "A", "C"}},
+		"T4": {Name: "T4", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusFROZEN},
+		"T5": {Name: "T5", BelongsToNodes: []string{"B"}},
+		"T6": {Name: "T6", BelongsToNodes: []string{"A"}},
+	}}}
+	before, _ := m.CopyShardingState()
+	loads := map[string]int{"B": 1, "C": 0}
+
+	plan := m.DrainPlan("A", []string{"C", "B", "A"}, loads)
+	assert.Equal(t, DrainPlan{Node: "A", Steps: []DrainStep{
+		{Tenant: "T1", Action: DrainRelocate, Target: "C"},
+		{Tenant: "T2", Action: DrainRelocate, Target: "C"},
+		{Tenant: "T3", Action: DrainFreeze},
+		{Tenant: "T6", Action: DrainRelocate, Target: "B"},
+	}}, plan)
+	assert.Equal(t, plan, m.DrainPlan("A", []string{"B", "C"}, loads), "deterministic")
+	assert.Equal(t, map[string]int{"B": 1, "C": 0}, loads)
+
+	after, _ := m.CopyShardingState()
+	assert.Equal(t, before, after)
+}
+
+func TestMetaClassMigrationImpact(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"A"}},
+		"S3": {Name: "S3", BelongsToNodes: []string{"B"}},
+	}}}
+
+	shards, nodes := m.MigrationImpact(nil)
+	assert.Equal(t, 0, shards)
+	assert.Equal(t, 0, nodes)
+
+	shards, nodes = m.MigrationImpact([]ShardMove{
+		{Shard: "S1", From: "A", To: "C"},
+		{Shard: "S2", From: "A", To: "B"},
+		{Shard: "S3", From: "B", To: "B"},
+		{Shard: "S4", From: "D", To: "E"},
+	})
+	assert.Equal(t, 2, shards)
+	assert.Equal(t, 3, nodes)
+}
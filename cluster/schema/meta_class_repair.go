@@ -0,0 +1,301 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+// setShard stores the repaired shard p under name, records the change and sets the shard version to v.
+// The caller must hold the write lock.
+func (m *metaClass) setShard(name string, p sharding.Physical, v uint64, modifiedAt time.Time) {
+	p.LastModifiedUnix = unixMilli(modifiedAt)
+	m.Sharding.Physical[name] = p
+	m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: name, Type: TenantUpdated, Status: p.Status, Version: v})
+	m.ShardVersion = v
+}
+
+// dedupeNodes returns nodes without duplicates. The first occurrence of a node is kept.
+func dedupeNodes(nodes []string) []string {
+	seen := make(map[string]struct{}, len(nodes))
+	res := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if _, ok := seen[node]; ok {
+			continue
+		}
+		seen[node] = struct{}{}
+		res = append(res, node)
+	}
+	return res
+}
+
+// DuplicateShardOwners returns the sorted names of the shards which have a node more than once
+func (m *metaClass) DuplicateShardOwners() []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if len(dedupeNodes(p.BelongsToNodes)) != len(p.BelongsToNodes) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// DedupeShardOwners removes duplicate nodes from the node list of the specified shards.
+// The first occurrence of a node is kept, so the primary and the order of the others are preserved.
+// Shards which don't exist or don't have duplicate nodes are skipped.
+func (m *metaClass) DedupeShardOwners(shards []string, v uint64, modifiedAt time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	for _, name := range shards {
+		p, ok := m.Sharding.Physical[name]
+		if !ok {
+			continue
+		}
+		nodes := dedupeNodes(p.BelongsToNodes)
+		if len(nodes) == len(p.BelongsToNodes) {
+			continue
+		}
+		p = p.DeepCopy()
+		p.BelongsToNodes = nodes
+		m.setShard(name, p, v, modifiedAt)
+	}
+	return nil
+}
+
+// VerifyTenantShardMapping returns the sorted keys of the physical shards
+// whose name doesn't match the key they are stored under
+func (m *metaClass) VerifyTenantShardMapping() []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for key, p := range m.Sharding.Physical {
+		if p.Name != key {
+			res = append(res, key)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// firstNonBlank returns the index of the first non-blank node, which is len(nodes) if all nodes are blank
+func firstNonBlank(nodes []string) int {
+	i := 0
+	for i < len(nodes) && nodes[i] == "" {
+		i++
+	}
+	return i
+}
+
+// BlankPrimaries returns the sorted names of the shards with a blank primary which can be repaired
+// by RepairBlankPrimaries and of the ones which can't because they don't have any non-blank node
+func (m *metaClass) BlankPrimaries() (repairable, unrepairable []string) {
+	m.RLock()
+	defer m.RUnlock()
+
+	for name, p := range m.Sharding.Physical {
+		if len(p.BelongsToNodes) > 0 && p.BelongsToNodes[0] != "" {
+			continue
+		}
+		if firstNonBlank(p.BelongsToNodes) == len(p.BelongsToNodes) {
+			unrepairable = append(unrepairable, name)
+		} else {
+			repairable = append(repairable, name)
+		}
+	}
+	sort.Strings(repairable)
+	sort.Strings(unrepairable)
+	return repairable, unrepairable
+}
+
+// RepairBlankPrimaries promotes the first non-blank node of the specified shards with a blank primary
+// by removing the blank entries before it. Shards which don't exist, have a primary or don't have
+// any non-blank node are skipped.
+func (m *metaClass) RepairBlankPrimaries(shards []string, v uint64, modifiedAt time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	for _, name := range shards {
+		p, ok := m.Sharding.Physical[name]
+		if !ok || len(p.BelongsToNodes) == 0 || p.BelongsToNodes[0] != "" {
+			continue
+		}
+		i := firstNonBlank(p.BelongsToNodes)
+		if i == len(p.BelongsToNodes) {
+			continue
+		}
+		p = p.DeepCopy()
+		p.BelongsToNodes = p.BelongsToNodes[i:]
+		m.setShard(name, p, v, modifiedAt)
+	}
+	return nil
+}
+
+// EmptyStatusTenants returns the sorted names of the tenants with an empty status
+func (m *metaClass) EmptyStatusTenants() []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if p.Status == "" {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// NormalizeEmptyStatuses sets the status of the specified tenants with an empty status to defaultStatus,
+// which must be a known tenant status. Tenants with an empty status are HOT, so any other default status
+// is applied through UpdateTenants and the returned request has to be applied by the DB of nodeID.
+// Tenants which don't exist or have a status are skipped.
+func (m *metaClass) NormalizeEmptyStatuses(nodeID string, tenants []string, defaultStatus string, v uint64, modifiedAt time.Time) (*command.UpdateTenantsRequest, error) {
+	if !isKnownTenantStatus(defaultStatus) {
+		return nil, fmt.Errorf("%w: invalid default tenant status %q", ErrUnknownTenantStatus, defaultStatus)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	req := &command.UpdateTenantsRequest{}
+	for _, name := range tenants {
+		p, ok := m.Sharding.Physical[name]
+		if !ok || p.Status != "" {
+			continue
+		}
+		if defaultStatus != models.TenantActivityStatusHOT {
+			req.Tenants = append(req.Tenants, &command.Tenant{Name: name, Status: defaultStatus})
+			continue
+		}
+		p = p.DeepCopy()
+		p.Status = defaultStatus
+		m.setShard(name, p, v, modifiedAt)
+	}
+	if len(req.Tenants) == 0 {
+		return req, nil
+	}
+	return req, m.updateTenants(nodeID, req, v, modifiedAt)
+}
+
+// remapNodes returns the remapped nodes of every shard which has a node which is a key of mapping.
+// It fails if the mapping would assign a node twice to the same shard. The caller must hold the lock.
+func (m *metaClass) remapNodes(mapping map[string]string) (map[string][]string, error) {
+	updated := make(map[string][]string)
+	for name, p := range m.Sharding.Physical {
+		nodes := make([]string, len(p.BelongsToNodes))
+		counts := make(map[string]int, len(nodes))
+		targets := make(map[string]struct{})
+		for i, node := range p.BelongsToNodes {
+			if to, ok := mapping[node]; ok && to != node {
+				node = to
+				targets[node] = struct{}{}
+			}
+			counts[node]++
+			nodes[i] = node
+		}
+		// duplicates which already existed before the remapping are left as they are
+		for node := range targets {
+			if counts[node] > 1 {
+				return nil, fmt.Errorf("shard %q: node %q would be assigned twice", name, node)
+			}
+		}
+		if len(targets) > 0 {
+			updated[name] = nodes
+		}
+	}
+	return updated, nil
+}
+
+// RemapNodesPlan returns the sorted names of the shards which RemapNodes would change
+func (m *metaClass) RemapNodesPlan(mapping map[string]string) ([]string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	updated, err := m.remapNodes(mapping)
+	if err != nil {
+		return nil, err
+	}
+	changed := make([]string, 0, len(updated))
+	for name := range updated {
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// RemapNodes replaces the nodes of every shard which are keys of mapping by their mapped node,
+// preserving the order of the nodes. It fails without modifying any shard if the mapping
+// would assign a node twice to the same shard.
+func (m *metaClass) RemapNodes(mapping map[string]string, v uint64, modifiedAt time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	updated, err := m.remapNodes(mapping)
+	if err != nil {
+		return err
+	}
+	changed := make([]string, 0, len(updated))
+	for name := range updated {
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	for _, name := range changed {
+		p := m.Sharding.Physical[name].DeepCopy()
+		p.BelongsToNodes = updated[name]
+		m.setShard(name, p, v, modifiedAt)
+	}
+	return nil
+}
+
+// ResetTenant replaces the shard of tenant by a new shard with the same name, status and a copy of nodes,
+// which must be a non-empty list of distinct nodes. Replication factor overrides and metadata are dropped.
+func (m *metaClass) ResetTenant(tenant, status string, nodes []string, v uint64, modifiedAt time.Time) error {
+	if !isKnownTenantStatus(status) {
+		return fmt.Errorf("invalid tenant status %q", status)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	name := m.tenantName(tenant)
+	if _, ok := m.Sharding.Physical[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrShardNotFound, tenant)
+	}
+	if err := validateShardNodes(name, nodes); err != nil {
+		return err
+	}
+	p := sharding.Physical{
+		Name:           name,
+		BelongsToNodes: nodes,
+		OwnsPercentage: 1.0,
+		Status:         status,
+	}
+	m.setShard(name, p.DeepCopy(), v, modifiedAt)
+	return nil
+}
@@ -0,0 +1,193 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassDedupeShardOwners(t *testing.T) {
+	nodes := []string{"A", "B", "A", "C", "B"}
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: nodes},
+		"S2": {Name: "S2", BelongsToNodes: []string{"A", "B"}},
+		"S3": {Name: "S3", BelongsToNodes: []string{"C", "C"}},
+	}}}
+
+	fixed := m.DuplicateShardOwners()
+	assert.Equal(t, []string{"S1", "S3"}, fixed)
+	require.Nil(t, m.DedupeShardOwners(append(fixed, "S2", "S4"), 2, time.UnixMilli(3)))
+	assert.Equal(t, []string{"A", "B", "C"}, m.Sharding.Physical["S1"].BelongsToNodes)
+	assert.Equal(t, []string{"A", "B"}, m.Sharding.Physical["S2"].BelongsToNodes)
+	assert.Equal(t, []string{"C"}, m.Sharding.Physical["S3"].BelongsToNodes)
+	assert.Equal(t, []string{"A", "B", "A", "C", "B"}, nodes, "original slice must not be modified")
+	assert.Equal(t, int64(3), m.Sharding.Physical["S1"].LastModifiedUnix)
+	assert.Equal(t, int64(0), m.Sharding.Physical["S2"].LastModifiedUnix)
+	assert.Equal(t, uint64(2), m.ShardVersion)
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "S1", Type: TenantUpdated, Version: 2},
+		{Tenant: "S3", Type: TenantUpdated, Version: 2},
+	}, changes)
+
+	assert.Empty(t, m.DuplicateShardOwners())
+}
+
+func TestMetaClassVerifyTenantShardMapping(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1"},
+		"T3": {Name: "T2"},
+		"T2": {},
+	}}}
+	assert.Equal(t, []string{"T2", "T3"}, m.VerifyTenantShardMapping())
+
+	m.Sharding.Physical = map[string]sharding.Physical{"T1": {Name: "T1"}}
+	assert.Empty(t, m.VerifyTenantShardMapping())
+}
+
+func TestMetaClassRepairBlankPrimaries(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"", "B", "C"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"A", "B"}},
+		"S3": {Name: "S3", BelongsToNodes: []string{"", "", "C", ""}},
+		"S4": {Name: "S4", BelongsToNodes: []string{"", ""}},
+		"S5": {Name: "S5"},
+	}}}
+
+	fixed, unrepairable := m.BlankPrimaries()
+	assert.Equal(t, []string{"S1", "S3"}, fixed)
+	assert.Equal(t, []string{"S4", "S5"}, unrepairable)
+
+	require.Nil(t, m.RepairBlankPrimaries([]string{"S1", "S2", "S3", "S4", "S5", "S6"}, 2, time.UnixMilli(4)))
+	assert.Equal(t, []string{"B", "C"}, m.Sharding.Physical["S1"].BelongsToNodes)
+	assert.Equal(t, []string{"A", "B"}, m.Sharding.Physical["S2"].BelongsToNodes)
+	assert.Equal(t, []string{"C", ""}, m.Sharding.Physical["S3"].BelongsToNodes)
+	assert.Equal(t, []string{"", ""}, m.Sharding.Physical["S4"].BelongsToNodes)
+	assert.Equal(t, int64(4), m.Sharding.Physical["S3"].LastModifiedUnix)
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "S1", Type: TenantUpdated, Version: 2},
+		{Tenant: "S3", Type: TenantUpdated, Version: 2},
+	}, changes)
+
+	fixed, unrepairable = m.BlankPrimaries()
+	assert.Empty(t, fixed)
+	assert.Equal(t, []string{"S4", "S5"}, unrepairable)
+}
+
+func TestMetaClassNormalizeEmptyStatuses(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusCOLD},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A"}},
+		"T0": {Name: "T0", BelongsToNodes: []string{"B"}},
+		"T3": {Name: "T3", BelongsToNodes: []string{"A"}},
+	}}}
+	assert.Equal(t, []string{"T0", "T2", "T3"}, m.EmptyStatusTenants())
+
+	_, err := m.NormalizeEmptyStatuses("A", []string{"T2"}, "WARM", 1, time.Time{})
+	assert.ErrorIs(t, err, ErrUnknownTenantStatus)
+	assert.Equal(t, "", m.Sharding.Physical["T2"].Status)
+
+	req, err := m.NormalizeEmptyStatuses("A", []string{"T0", "T1", "T2", "T4"}, models.TenantActivityStatusHOT, 2, time.UnixMilli(3))
+	require.Nil(t, err)
+	assert.Empty(t, req.Tenants, "empty statuses are HOT already")
+	assert.Equal(t, models.TenantActivityStatusHOT, m.Sharding.Physical["T0"].Status)
+	assert.Equal(t, models.TenantActivityStatusHOT, m.Sharding.Physical["T2"].Status)
+	assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T1"].Status)
+	assert.Equal(t, int64(3), m.Sharding.Physical["T2"].LastModifiedUnix)
+
+	req, err = m.NormalizeEmptyStatuses("A", []string{"T3"}, models.TenantActivityStatusCOLD, 3, time.UnixMilli(4))
+	require.Nil(t, err)
+	assert.Equal(t, []*command.Tenant{{Name: "T3", Status: models.TenantActivityStatusCOLD}}, req.Tenants)
+	assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T3"].Status)
+
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "T0", Type: TenantUpdated, Status: models.TenantActivityStatusHOT, Version: 2},
+		{Tenant: "T2", Type: TenantUpdated, Status: models.TenantActivityStatusHOT, Version: 2},
+		{Tenant: "T3", Type: TenantUpdated, Status: models.TenantActivityStatusCOLD, Version: 3},
+	}, changes)
+	assert.Empty(t, m.EmptyStatusTenants())
+}
+
+func TestMetaClassRemapNodes(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusHOT},
+		"S2": {Name: "S2", BelongsToNodes: []string{"B", "C"}, Status: models.TenantActivityStatusHOT},
+		"S3": {Name: "S3", BelongsToNodes: []string{"C", "C"}, Status: models.TenantActivityStatusHOT},
+	}}}
+
+	_, err := m.RemapNodesPlan(map[string]string{"A": "B"})
+	assert.NotNil(t, err)
+	assert.NotNil(t, m.RemapNodes(map[string]string{"A": "B"}, 1, time.Time{}))
+	assert.Equal(t, []string{"A", "B"}, m.Sharding.Physical["S1"].BelongsToNodes)
+
+	mapping := map[string]string{"B": "D", "C": "C"}
+	changed, err := m.RemapNodesPlan(mapping)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"S1", "S2"}, changed)
+	assert.Equal(t, []string{"A", "B"}, m.Sharding.Physical["S1"].BelongsToNodes, "plan is read-only")
+
+	require.Nil(t, m.RemapNodes(mapping, 2, time.UnixMilli(3)))
+	assert.Equal(t, []string{"A", "D"}, m.Sharding.Physical["S1"].BelongsToNodes)
+	assert.Equal(t, []string{"D", "C"}, m.Sharding.Physical["S2"].BelongsToNodes)
+	assert.Equal(t, []string{"C", "C"}, m.Sharding.Physical["S3"].BelongsToNodes)
+	assert.Equal(t, int64(3), m.Sharding.Physical["S2"].LastModifiedUnix)
+	assert.Equal(t, uint64(2), m.ShardVersion)
+
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "S1", Type: TenantUpdated, Status: models.TenantActivityStatusHOT, Version: 2},
+		{Tenant: "S2", Type: TenantUpdated, Status: models.TenantActivityStatusHOT, Version: 2},
+	}, changes)
+}
+
+func TestMetaClassResetTenant(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {
+				Name:              "T1",
+				BelongsToNodes:    []string{"A", "", "A"},
+				Status:            models.TenantActivityStatusFROZEN,
+				ReplicationFactor: 3,
+				Metadata:          map[string]string{"tier": "free"},
+			},
+		}},
+	}
+
+	assert.ErrorIs(t, m.ResetTenant("T2", models.TenantActivityStatusHOT, []string{"A"}, 1, time.Time{}), ErrShardNotFound)
+	assert.NotNil(t, m.ResetTenant("T1", "WARM", []string{"A"}, 1, time.Time{}))
+	assert.NotNil(t, m.ResetTenant("T1", models.TenantActivityStatusHOT, []string{"A", "A"}, 1, time.Time{}))
+	assert.Equal(t, models.TenantActivityStatusFROZEN, m.Sharding.Physical["T1"].Status)
+
+	nodes := []string{"B", "C"}
+	require.Nil(t, m.ResetTenant("T1", models.TenantActivityStatusCOLD, nodes, 2, time.UnixMilli(3)))
+	nodes[0] = "X"
+	assert.Equal(t, sharding.Physical{
+		Name:             "T1",
+		BelongsToNodes:   []string{"B", "C"},
+		OwnsPercentage:   1.0,
+		Status:           models.TenantActivityStatusCOLD,
+		LastModifiedUnix: 3,
+	}, m.Sharding.Physical["T1"])
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "T1", Type: TenantUpdated, Status: models.TenantActivityStatusCOLD, Version: 2},
+	}, changes)
+}
@@ -0,0 +1,475 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/usecases/sharding"
+	"golang.org/x/exp/slices"
+)
+
+// replicationFactor returns the class replication factor, which is at least 1
+func (m *metaClass) replicationFactor() int64 {
+	if m.Class.ReplicationConfig != nil && m.Class.ReplicationConfig.Factor > 1 {
+		return m.Class.ReplicationConfig.Factor
+	}
+	return 1
+}
+
+// tenantReplicationFactor returns the replication factor override of p if set
+// and falls back to the class replication factor otherwise
+func (m *metaClass) tenantReplicationFactor(p *sharding.Physical) int64 {
+	if p.ReplicationFactor > 0 {
+		return p.ReplicationFactor
+	}
+	return m.replicationFactor()
+}
+
+// SetTenantReplicationFactor overrides the class replication factor for the specified tenant.
+// A factor of 0 removes the override. Any other factor must not exceed the number of nodes,
+// which are the nodes of the cluster at the time of the request.
+// It is applied through the TYPE_UPDATE_TENANT_REPLICATION_FACTOR command only, since
+// the override decides the placement of the tenant when it is unfrozen.
+func (m *metaClass) SetTenantReplicationFactor(tenant string, factor int, nodes []string, v uint64) error {
+	if factor < 0 {
+		return fmt.Errorf("negative replication factor: %d", factor)
+	}
+	if factor > len(nodes) {
+		return fmt.Errorf("not enough nodes for replication factor: found %d want %d", len(nodes), factor)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	tenant = m.tenantName(tenant)
+	p, ok := m.Sharding.Physical[tenant]
+	if !ok {
+		return ErrShardNotFound
+	}
+	if p.ReplicationFactor != int64(factor) {
+		p = p.DeepCopy()
+		p.ReplicationFactor = int64(factor)
+		m.Sharding.Physical[tenant] = p
+		m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: tenant, Type: TenantUpdated, Status: p.Status, Version: v})
+	}
+	m.ShardVersion = v
+	return nil
+}
+
+// NodesNeededForQuorum returns for each shard which lost the majority of its replicas
+// the candidate nodes it would have to take on to regain quorum.
+// Candidates already owning the shard are never selected and assignments are spread
+// so that the least used candidate is picked first. The state is not mutated.
+func (m *metaClass) NodesNeededForQuorum(liveNodes, candidateNodes map[string]bool) (map[string][]string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	candidates := make([]string, 0, len(candidateNodes))
+	for node, ok := range candidateNodes {
+		if ok {
+			candidates = append(candidates, node)
+		}
+	}
+	sort.Strings(candidates)
+
+	shards := make([]string, 0, len(m.Sharding.Physical))
+	for name := range m.Sharding.Physical {
+		shards = append(shards, name)
+	}
+	sort.Strings(shards)
+
+	assigned := make(map[string]int, len(candidates))
+	res := make(map[string][]string)
+	for _, name := range shards {
+		nodes := m.Sharding.Physical[name].BelongsToNodes
+		live := 0
+		for _, node := range nodes {
+			if liveNodes[node] {
+				live++
+			}
+		}
+		// every added node also grows the replica set, so the live replicas must
+		// outnumber the dead ones: live+needed > len(nodes)-live
+		needed := len(nodes) - 2*live + 1
+		if needed <= 0 {
+			continue
+		}
+
+		selected := selectReplicaNodes(candidates, nodes, needed, assigned)
+		if len(selected) < needed {
+			return nil, fmt.Errorf("shard %q: not enough candidates to restore quorum: found %d want %d",
+				name, len(selected), needed)
+		}
+		res[name] = selected
+	}
+	return res, nil
+}
+
+// selectReplicaNodes selects up to n of the sorted candidates which are not in owners.
+// Candidates with the lowest count in assigned are selected first and the counts
+// of the selected nodes are incremented.
+func selectReplicaNodes(candidates, owners []string, n int, assigned map[string]int) []string {
+	eligible := make([]string, 0, len(candidates))
+	for _, node := range candidates {
+		if !slices.Contains(owners, node) {
+			eligible = append(eligible, node)
+		}
+	}
+	sort.SliceStable(eligible, func(i, j int) bool {
+		return assigned[eligible[i]] < assigned[eligible[j]]
+	})
+	if len(eligible) > n {
+		eligible = eligible[:n]
+	}
+	for _, node := range eligible {
+		assigned[node]++
+	}
+	return eligible
+}
+
+// EstimateNodeStorage returns a rough estimate of the bytes stored by each node owning a replica,
+// assuming every replica takes bytesPerTenant bytes
+func (m *metaClass) EstimateNodeStorage(bytesPerTenant int64) map[string]int64 {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make(map[string]int64)
+	for _, p := range m.Sharding.Physical {
+		for _, node := range p.BelongsToNodes {
+			if node != "" {
+				res[node] += bytesPerTenant
+			}
+		}
+	}
+	return res
+}
+
+// ProjectedNodeLoad returns the number of replicas each node would own if the replication factor
+// of all shards was raised to newFactor, using candidateNodes for the additional replicas.
+// Replicas are placed the same way as by NodesNeededForQuorum, preferring the least loaded candidates.
+// Shards which already have newFactor or more replicas are left as is. The state is not mutated.
+func (m *metaClass) ProjectedNodeLoad(newFactor int, candidateNodes []string) map[string]int {
+	m.RLock()
+	defer m.RUnlock()
+
+	candidates := make([]string, 0, len(candidateNodes))
+	for _, node := range candidateNodes {
+		if !slices.Contains(candidates, node) {
+			candidates = append(candidates, node)
+		}
+	}
+	sort.Strings(candidates)
+
+	shards := make([]string, 0, len(m.Sharding.Physical))
+	load := make(map[string]int, len(candidates))
+	for name, p := range m.Sharding.Physical {
+		shards = append(shards, name)
+		for _, node := range p.BelongsToNodes {
+			load[node]++
+		}
+	}
+	sort.Strings(shards)
+
+	for _, name := range shards {
+		nodes := m.Sharding.Physical[name].BelongsToNodes
+		if needed := newFactor - len(nodes); needed > 0 {
+			selectReplicaNodes(candidates, nodes, needed, load)
+		}
+	}
+	return load
+}
+
+// EffectiveReplication returns for each shard the number of its replicas stored on live nodes
+func (m *metaClass) EffectiveReplication(liveNodes map[string]bool) map[string]int {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make(map[string]int, len(m.Sharding.Physical))
+	for name, p := range m.Sharding.Physical {
+		n := 0
+		for _, node := range p.BelongsToNodes {
+			if liveNodes[node] {
+				n++
+			}
+		}
+		res[name] = n
+	}
+	return res
+}
+
+// EffectiveFactorForTenant returns the replication factor of tenant, which is its override
+// if set and the class replication factor otherwise
+func (m *metaClass) EffectiveFactorForTenant(tenant string) (int, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	p, ok := m.Sharding.Physical[m.tenantName(tenant)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrShardNotFound, tenant)
+	}
+	return int(m.tenantReplicationFactor(&p)), nil
+}
+
+// ShardDeficit is the number of replicas a shard is missing to reach its replication factor
+type ShardDeficit struct {
+	Shard    string
+	Replicas int
+	Factor   int64
+	Deficit  int64
+}
+
+// ShardsByDeficit returns all shards with less replicas than their replication factor.
+// The most degraded shards come first, ties are broken by shard name.
+func (m *metaClass) ShardsByDeficit() []ShardDeficit {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]ShardDeficit, 0)
+	for name, p := range m.Sharding.Physical {
+		factor := m.tenantReplicationFactor(&p)
+		if n := int64(len(p.BelongsToNodes)); n < factor {
+			res = append(res, ShardDeficit{
+				Shard:    name,
+				Replicas: len(p.BelongsToNodes),
+				Factor:   factor,
+				Deficit:  factor - n,
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Deficit != res[j].Deficit {
+			return res[i].Deficit > res[j].Deficit
+		}
+		return res[i].Shard < res[j].Shard
+	})
+	return res
+}
+
+// VerifyReplicationSatisfied returns the shards with less distinct replicas than their
+// replication factor mapped to the number of missing replicas. Per shard replication
+// factor overrides are taken into account, blank and duplicate nodes don't count as replicas.
+func (m *metaClass) VerifyReplicationSatisfied() map[string]int {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make(map[string]int)
+	for name, p := range m.Sharding.Physical {
+		distinct := make(map[string]struct{}, len(p.BelongsToNodes))
+		for _, node := range p.BelongsToNodes {
+			if node != "" {
+				distinct[node] = struct{}{}
+			}
+		}
+		if missing := m.tenantReplicationFactor(&p) - int64(len(distinct)); missing > 0 {
+			res[name] = int(missing)
+		}
+	}
+	return res
+}
+
+// SinglePointOfFailureShards returns the sorted names of the shards owned by a single node
+// although their replication factor is greater than 1
+func (m *metaClass) SinglePointOfFailureShards() []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if len(p.BelongsToNodes) == 1 && m.tenantReplicationFactor(&p) > 1 {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// TenantsWithFewerThan returns the sorted names of the shards owned by less than minNodes nodes
+func (m *metaClass) TenantsWithFewerThan(minNodes int) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if len(p.BelongsToNodes) < minNodes {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// TotalReplicaSlots returns the number of replicas of all shards
+func (m *metaClass) TotalReplicaSlots() int {
+	if m == nil {
+		return 0
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+
+	n := 0
+	for _, p := range m.Sharding.Physical {
+		n += len(p.BelongsToNodes)
+	}
+	return n
+}
+
+// BestReplicaCandidate returns the least loaded of candidateNodes which doesn't own shard yet.
+// Ties are broken by choosing the lexicographically smallest node name. nodeLoads isn't modified.
+func (m *metaClass) BestReplicaCandidate(shard string, candidateNodes []string, nodeLoads map[string]int) (string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	p, ok := m.Sharding.Physical[m.tenantName(shard)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrShardNotFound, shard)
+	}
+	candidates := slices.Clone(candidateNodes)
+	sort.Strings(candidates)
+	loads := make(map[string]int, len(nodeLoads))
+	for node, n := range nodeLoads {
+		loads[node] = n
+	}
+	selected := selectReplicaNodes(candidates, p.BelongsToNodes, 1, loads)
+	if len(selected) == 0 {
+		return "", fmt.Errorf("shard %q: no eligible replica candidate", shard)
+	}
+	return selected[0], nil
+}
+
+// HealReplicationBalanced returns the replicas to add to every shard with less replicas than its
+// replication factor. The candidates are the nodes of nodeLoads. For each missing replica the least loaded
+// candidate not owning the shard yet is picked and its load is incremented, so the whole batch stays balanced.
+// Shards which can't reach their factor get as many replicas as possible.
+// It returns the selected nodes per shard, to be applied with AddReplicas, and the updated loads.
+func (m *metaClass) HealReplicationBalanced(nodeLoads map[string]int) (plan map[string][]string, newLoads map[string]int) {
+	m.RLock()
+	defer m.RUnlock()
+
+	candidates := make([]string, 0, len(nodeLoads))
+	newLoads = make(map[string]int, len(nodeLoads))
+	for node, load := range nodeLoads {
+		candidates = append(candidates, node)
+		newLoads[node] = load
+	}
+	sort.Strings(candidates)
+
+	plan, _ = m.planReplicaHealing(candidates, newLoads, m.tenantReplicationFactor)
+	return plan, newLoads
+}
+
+// AddReplicas appends the nodes of replicas to the nodes of their shard.
+// If a shard doesn't exist anymore or a node already owns its shard, nothing is applied and
+// an error wrapping ErrRebalanceConflict is returned. The returned request holds the shards
+// which got a replica on nodeID.
+func (m *metaClass) AddReplicas(nodeID string, replicas map[string][]string, v uint64, modifiedAt time.Time) (*command.AddTenantsRequest, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	shards := make([]string, 0, len(replicas))
+	for name, nodes := range replicas {
+		p, ok := m.Sharding.Physical[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: shard %q: %w", ErrRebalanceConflict, name, ErrShardNotFound)
+		}
+		for i, node := range nodes {
+			if slices.Contains(p.BelongsToNodes, node) || slices.Contains(nodes[:i], node) {
+				return nil, fmt.Errorf("%w: shard %q: node %q already owns a replica", ErrRebalanceConflict, name, node)
+			}
+		}
+		shards = append(shards, name)
+	}
+	sort.Strings(shards)
+
+	req := &command.AddTenantsRequest{}
+	for _, name := range shards {
+		p := m.Sharding.Physical[name].DeepCopy()
+		p.BelongsToNodes = append(p.BelongsToNodes, replicas[name]...)
+		m.setShard(name, p, v, modifiedAt)
+		if slices.Contains(replicas[name], nodeID) {
+			req.Tenants = append(req.Tenants, &command.Tenant{Name: name, Status: p.ActivityStatus()})
+		}
+	}
+	return req, nil
+}
+
+// planReplicaHealing selects for every shard with less replicas than factor(shard) the least assigned
+// of the sorted candidates which don't own the shard yet, incrementing their count in assigned.
+// It returns the selected nodes per shard and the sorted names of the shards which can't reach their factor.
+func (m *metaClass) planReplicaHealing(candidates []string, assigned map[string]int,
+	factor func(p *sharding.Physical) int64,
+) (added map[string][]string, unsatisfied []string) {
+	shards := make([]string, 0, len(m.Sharding.Physical))
+	for name := range m.Sharding.Physical {
+		shards = append(shards, name)
+	}
+	sort.Strings(shards)
+
+	added = make(map[string][]string)
+	for _, name := range shards {
+		p := m.Sharding.Physical[name]
+		needed := int(factor(&p)) - len(p.BelongsToNodes)
+		if needed <= 0 {
+			continue
+		}
+		selected := selectReplicaNodes(candidates, p.BelongsToNodes, needed, assigned)
+		if len(selected) < needed {
+			unsatisfied = append(unsatisfied, name)
+		}
+		if len(selected) > 0 {
+			added[name] = selected
+		}
+	}
+	return added, unsatisfied
+}
+
+// RaiseFactorPlan returns the replicas needed to raise the replication factor of the class to newFactor,
+// choosing the least loaded of candidateNodes like HealReplicationBalanced. Per shard replication factor
+// overrides are kept. It fails if newFactor is lower than the current factor or if any shard can't reach
+// its factor with the candidates. nodeLoads isn't modified.
+func (m *metaClass) RaiseFactorPlan(newFactor int, candidateNodes []string, nodeLoads map[string]int) (map[string][]string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	if current := m.replicationFactor(); int64(newFactor) < current {
+		return nil, fmt.Errorf("replication factor %d is lower than current factor %d", newFactor, current)
+	}
+
+	candidates := make([]string, 0, len(candidateNodes))
+	for _, node := range candidateNodes {
+		if !slices.Contains(candidates, node) {
+			candidates = append(candidates, node)
+		}
+	}
+	sort.Strings(candidates)
+	loads := make(map[string]int, len(nodeLoads))
+	for node, n := range nodeLoads {
+		loads[node] = n
+	}
+
+	added, unsatisfied := m.planReplicaHealing(candidates, loads, func(p *sharding.Physical) int64 {
+		if p.ReplicationFactor > 0 {
+			return p.ReplicationFactor
+		}
+		return int64(newFactor)
+	})
+	if len(unsatisfied) > 0 {
+		return nil, fmt.Errorf("shards %v: not enough candidate nodes for replication factor %d", unsatisfied, newFactor)
+	}
+	return added, nil
+}
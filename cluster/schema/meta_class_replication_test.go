@@ -0,0 +1,329 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassTenantReplicationFactor(t *testing.T) {
+	m := &metaClass{
+		Class: models.Class{
+			Class:             "C",
+			ReplicationConfig: &models.ReplicationConfig{Factor: 1},
+		},
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusFROZEN},
+		}},
+	}
+
+	nodes := []string{"A", "B", "C"}
+	assert.ErrorIs(t, m.SetTenantReplicationFactor("X", 2, nodes, 1), ErrShardNotFound)
+	assert.NotNil(t, m.SetTenantReplicationFactor("T1", -1, nodes, 2))
+	assert.ErrorContains(t, m.SetTenantReplicationFactor("T1", 4, nodes, 2), "not enough nodes")
+	assert.Zero(t, m.Sharding.Physical["T1"].ReplicationFactor)
+
+	// override survives a deep copy of the sharding state
+	require.Nil(t, m.SetTenantReplicationFactor("T1", 3, nodes, 3))
+	st, _ := m.CopyShardingState()
+	assert.Equal(t, int64(3), st.Physical["T1"].ReplicationFactor)
+
+	// the cluster shrank below the override
+	req := &command.UpdateTenantsRequest{
+		Tenants:      []*command.Tenant{{Name: "T1", Status: models.TenantActivityStatusCOLD}},
+		ClusterNodes: []string{"A", "B"},
+	}
+	assert.NotNil(t, m.UpdateTenants("A", req, 2, time.Time{}))
+
+	require.Nil(t, m.SetTenantReplicationFactor("T1", 2, nodes, 4))
+	req = &command.UpdateTenantsRequest{
+		Tenants:      []*command.Tenant{{Name: "T1", Status: models.TenantActivityStatusCOLD}},
+		ClusterNodes: []string{"A", "B"},
+	}
+	require.Nil(t, m.UpdateTenants("A", req, 3, time.Time{}))
+	assert.Len(t, m.Sharding.Physical["T1"].BelongsToNodes, 2)
+
+	// removing the override falls back to the class factor
+	require.Nil(t, m.SetTenantReplicationFactor("T1", 0, nil, 5))
+	assert.Equal(t, int64(1), m.tenantReplicationFactor(&sharding.Physical{}))
+}
+
+func TestMetaClassNodesNeededForQuorum(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A", "B", "C"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"A", "B", "C"}},
+			"S3": {Name: "S3", BelongsToNodes: []string{"A", "D", "E"}},
+		}},
+	}
+	live := map[string]bool{"A": true, "D": true}
+
+	// 1 of 3 replicas live: 2 new nodes are needed for 3 of 5
+	res, err := m.NodesNeededForQuorum(live, map[string]bool{"X": true, "Y": true, "Z": true})
+	require.Nil(t, err)
+	assert.Equal(t, map[string][]string{"S1": {"X", "Y"}, "S2": {"Z", "X"}}, res)
+
+	_, err = m.NodesNeededForQuorum(live, map[string]bool{"X": true})
+	assert.NotNil(t, err)
+
+	_, err = m.NodesNeededForQuorum(map[string]bool{}, map[string]bool{"X": true})
+	assert.NotNil(t, err)
+}
+
+func TestMetaClassEffectiveReplication(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A", "B", "C"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"C"}},
+		}},
+	}
+	assert.Equal(t, map[string]int{"S1": 2, "S2": 0}, m.EffectiveReplication(map[string]bool{"A": true, "B": true}))
+}
+
+func TestMetaClassShardsByDeficit(t *testing.T) {
+	m := &metaClass{
+		Class: models.Class{ReplicationConfig: &models.ReplicationConfig{Factor: 3}},
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A", "B", "C"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"A", "B"}},
+			"S3": {Name: "S3", BelongsToNodes: []string{"A"}},
+			"S4": {Name: "S4", BelongsToNodes: []string{"B"}, ReplicationFactor: 1},
+			"S0": {Name: "S0", BelongsToNodes: []string{"C"}},
+		}},
+	}
+	want := []ShardDeficit{
+		{Shard: "S0", Replicas: 1, Factor: 3, Deficit: 2},
+		{Shard: "S3", Replicas: 1, Factor: 3, Deficit: 2},
+		{Shard: "S2", Replicas: 2, Factor: 3, Deficit: 1},
+	}
+	assert.Equal(t, want, m.ShardsByDeficit())
+}
+
+func TestMetaClassVerifyReplicationSatisfied(t *testing.T) {
+	m := &metaClass{
+		Class: models.Class{ReplicationConfig: &models.ReplicationConfig{Factor: 2}},
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}},
+			"T2": {Name: "T2", BelongsToNodes: []string{"A"}},
+			"T3": {Name: "T3", BelongsToNodes: []string{"A", "B"}, ReplicationFactor: 3},
+			"T4": {Name: "T4", BelongsToNodes: []string{"A"}, ReplicationFactor: 1},
+			"T5": {Name: "T5", BelongsToNodes: []string{"A", "A", ""}},
+		}},
+	}
+	assert.Equal(t, map[string]int{"T2": 1, "T3": 1, "T5": 1}, m.VerifyReplicationSatisfied())
+}
+
+func TestMetaClassEffectiveFactorForTenant(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1", BelongsToNodes: []string{"A"}},
+			"T2": {Name: "T2", BelongsToNodes: []string{"A"}, ReplicationFactor: 3},
+		}},
+	}
+	factor, err := m.EffectiveFactorForTenant("T1")
+	require.Nil(t, err)
+	assert.Equal(t, 1, factor)
+
+	m.Class.ReplicationConfig = &models.ReplicationConfig{Factor: 2}
+	factor, err = m.EffectiveFactorForTenant("T1")
+	require.Nil(t, err)
+	assert.Equal(t, 2, factor)
+	factor, err = m.EffectiveFactorForTenant("T2")
+	require.Nil(t, err)
+	assert.Equal(t, 3, factor)
+
+	_, err = m.EffectiveFactorForTenant("T3")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+}
+
+func TestMetaClassSinglePointOfFailureShards(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A", "B"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"A"}},
+			"S3": {Name: "S3", BelongsToNodes: []string{"B"}, ReplicationFactor: 1},
+			"S0": {Name: "S0", BelongsToNodes: []string{"C"}},
+		}},
+	}
+	assert.Empty(t, m.SinglePointOfFailureShards())
+
+	m.Class.ReplicationConfig = &models.ReplicationConfig{Factor: 2}
+	assert.Equal(t, []string{"S0", "S2"}, m.SinglePointOfFailureShards())
+}
+
+func TestMetaClassTenantsWithFewerThan(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1", BelongsToNodes: []string{"A", "B", "C"}},
+			"T2": {Name: "T2", BelongsToNodes: []string{"A"}},
+			"T3": {Name: "T3", BelongsToNodes: []string{"A", "B"}},
+			"T0": {Name: "T0"},
+		}},
+	}
+	assert.Empty(t, m.TenantsWithFewerThan(0))
+	assert.Equal(t, []string{"T0", "T2"}, m.TenantsWithFewerThan(2))
+	assert.Equal(t, []string{"T0", "T2", "T3"}, m.TenantsWithFewerThan(3))
+}
+
+func TestMetaClassTotalReplicaSlots(t *testing.T) {
+	var nilMeta *metaClass
+	assert.Equal(t, 0, nilMeta.TotalReplicaSlots())
+
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A", "B", "C"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"A"}},
+			"S3": {Name: "S3"},
+		}},
+	}
+	assert.Equal(t, 4, m.TotalReplicaSlots())
+}
+
+func TestMetaClassEstimateNodeStorage(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A", "B"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"A"}},
+			"S3": {Name: "S3", BelongsToNodes: []string{""}},
+		}},
+	}
+	assert.Equal(t, map[string]int64{"A": 2048, "B": 1024}, m.EstimateNodeStorage(1024))
+}
+
+func TestMetaClassBestReplicaCandidate(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A"}},
+		}},
+	}
+	loads := map[string]int{"A": 0, "B": 3, "C": 1, "D": 1}
+
+	node, err := m.BestReplicaCandidate("S1", []string{"B", "D", "A", "C"}, loads)
+	require.Nil(t, err)
+	assert.Equal(t, "C", node)
+	assert.Equal(t, map[string]int{"A": 0, "B": 3, "C": 1, "D": 1}, loads)
+
+	_, err = m.BestReplicaCandidate("S1", []string{"A"}, loads)
+	assert.NotNil(t, err)
+	_, err = m.BestReplicaCandidate("S2", []string{"B"}, loads)
+	assert.ErrorIs(t, err, ErrShardNotFound)
+}
+
+func TestMetaClassProjectedNodeLoad(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"B"}},
+			"S3": {Name: "S3", BelongsToNodes: []string{"A", "B"}},
+		}},
+	}
+	before, _ := m.CopyShardingState()
+
+	assert.Equal(t, map[string]int{"A": 2, "B": 2, "C": 2}, m.ProjectedNodeLoad(2, []string{"C", "B"}))
+	assert.Equal(t, map[string]int{"A": 3, "B": 3, "C": 3}, m.ProjectedNodeLoad(3, []string{"A", "B", "C"}))
+	assert.Equal(t, map[string]int{"A": 2, "B": 2}, m.ProjectedNodeLoad(1, []string{"C"}))
+
+	after, _ := m.CopyShardingState()
+	assert.Equal(t, before, after)
+}
+
+func TestMetaClassHealReplicationBalanced(t *testing.T) {
+	m := &metaClass{
+		Class: models.Class{ReplicationConfig: &models.ReplicationConfig{Factor: 2}},
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"A"}},
+			"S3": {Name: "S3", BelongsToNodes: []string{"B", "C"}},
+			"S4": {Name: "S4", BelongsToNodes: []string{"A"}, ReplicationFactor: 3},
+		}},
+	}
+	loads := map[string]int{"B": 5, "C": 1}
+
+	plan, newLoads := m.HealReplicationBalanced(loads)
+	assert.Equal(t, map[string][]string{"S1": {"C"}, "S2": {"C"}, "S4": {"C", "B"}}, plan)
+	assert.Equal(t, map[string]int{"B": 6, "C": 4}, newLoads)
+	assert.Equal(t, map[string]int{"B": 5, "C": 1}, loads)
+	assert.Equal(t, []string{"A"}, m.Sharding.Physical["S1"].BelongsToNodes, "planning is read-only")
+
+	req, err := m.AddReplicas("C", plan, 2, time.UnixMilli(3))
+	require.Nil(t, err)
+	assert.Equal(t, []string{"A", "C"}, m.Sharding.Physical["S1"].BelongsToNodes)
+	assert.Equal(t, []string{"A", "C"}, m.Sharding.Physical["S2"].BelongsToNodes)
+	assert.Equal(t, []string{"A", "C", "B"}, m.Sharding.Physical["S4"].BelongsToNodes)
+	assert.Equal(t, int64(3), m.Sharding.Physical["S4"].LastModifiedUnix)
+	assert.Len(t, req.Tenants, 3)
+	changes, _ := m.TenantChangesSince(0)
+	assert.Len(t, changes, 3)
+
+	// best effort: no candidate left for S5
+	m.Sharding.Physical["S5"] = sharding.Physical{Name: "S5", BelongsToNodes: []string{"B", "C"}, ReplicationFactor: 3}
+	plan, _ = m.HealReplicationBalanced(map[string]int{"B": 0, "C": 0})
+	assert.Empty(t, plan)
+}
+
+func TestMetaClassAddReplicas(t *testing.T) {
+	newMetaClass := func() *metaClass {
+		return &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"B"}},
+		}}}
+	}
+	tests := []struct {
+		name     string
+		replicas map[string][]string
+	}{
+		{"unknown shard", map[string][]string{"S1": {"B"}, "S3": {"A"}}},
+		{"node owns shard", map[string][]string{"S1": {"B"}, "S2": {"B"}}},
+		{"duplicate node", map[string][]string{"S1": {"B", "B"}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMetaClass()
+			_, err := m.AddReplicas("A", tc.replicas, 1, time.Time{})
+			assert.ErrorIs(t, err, ErrRebalanceConflict)
+			assert.Equal(t, newMetaClass().Sharding, m.Sharding, "nothing is applied")
+		})
+	}
+}
+
+func TestMetaClassRaiseFactorPlan(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A"}},
+			"S2": {Name: "S2", BelongsToNodes: []string{"B"}},
+			"S3": {Name: "S3", BelongsToNodes: []string{"C"}, ReplicationFactor: 1},
+		}},
+	}
+	loads := map[string]int{"A": 1, "B": 1, "C": 3}
+
+	plan, err := m.RaiseFactorPlan(2, []string{"C", "B", "A"}, loads)
+	require.Nil(t, err)
+	assert.Equal(t, map[string][]string{"S1": {"B"}, "S2": {"A"}}, plan)
+	assert.Nil(t, m.Class.ReplicationConfig, "planning is read-only")
+	assert.Equal(t, []string{"A"}, m.Sharding.Physical["S1"].BelongsToNodes)
+	assert.Equal(t, map[string]int{"A": 1, "B": 1, "C": 3}, loads)
+
+	m.Class.ReplicationConfig = &models.ReplicationConfig{Factor: 2}
+	_, err = m.RaiseFactorPlan(1, []string{"A"}, loads)
+	assert.NotNil(t, err)
+
+	// not enough candidates
+	_, err = m.RaiseFactorPlan(3, []string{"A", "B"}, loads)
+	assert.NotNil(t, err)
+}
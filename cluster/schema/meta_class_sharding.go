@@ -0,0 +1,189 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/usecases/sharding"
+	shardingcfg "github.com/weaviate/weaviate/usecases/sharding/config"
+	"golang.org/x/exp/slices"
+)
+
+// ShardingConfig returns a copy of the config of the sharding state
+func (m *metaClass) ShardingConfig() shardingcfg.Config {
+	m.RLock()
+	defer m.RUnlock()
+	return m.Sharding.Config.DeepCopy()
+}
+
+// VirtualShardCount returns the number of virtual shards per physical shard.
+// It falls back to the default if the sharding config doesn't set it.
+func (m *metaClass) VirtualShardCount() int {
+	m.RLock()
+	defer m.RUnlock()
+	if n := m.Sharding.Config.VirtualPerPhysical; n > 0 {
+		return n
+	}
+	return shardingcfg.DefaultVirtualPerPhysical
+}
+
+// ShardCountDrift returns the desired shard count of the sharding config, the actual number
+// of physical shards and the difference between them (actual - configured).
+func (m *metaClass) ShardCountDrift() (configured, actual, delta int) {
+	m.RLock()
+	defer m.RUnlock()
+	configured = m.Sharding.Config.DesiredCount
+	actual = len(m.Sharding.Physical)
+	return configured, actual, actual - configured
+}
+
+// AddShard adds the shard name owned by nodes with status.
+// nodes must be a non-empty list of distinct nodes and the shard must not exist yet.
+// If the class isn't partitioned, the virtual shards are redistributed so that the new shard
+// gets its share of the objects. The returned request holds the shard if it belongs to nodeID.
+func (m *metaClass) AddShard(nodeID, name string, nodes []string, status string, v uint64, modifiedAt time.Time) (*command.AddTenantsRequest, error) {
+	if err := validateShardNodes(name, nodes); err != nil {
+		return nil, err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	if _, ok := m.Sharding.Physical[name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrShardExists, name)
+	}
+	if m.Sharding.Physical == nil {
+		m.Sharding.Physical = make(map[string]sharding.Physical)
+	}
+	p := m.Sharding.AddPartition(name, slices.Clone(nodes), status)
+	p.LastModifiedUnix = unixMilli(modifiedAt)
+	m.Sharding.Physical[name] = p
+	m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: name, Type: TenantAdded, Status: p.Status, Version: v})
+	m.ShardVersion = v
+	for _, changed := range m.distributeVirtual() {
+		if changed != name { // the new shard is recorded as added already
+			m.setShard(changed, m.Sharding.Physical[changed], v, modifiedAt)
+		}
+	}
+
+	req := &command.AddTenantsRequest{}
+	if slices.Contains(nodes, nodeID) {
+		req.Tenants = []*command.Tenant{{Name: name, Status: status}}
+	}
+	return req, nil
+}
+
+// distributeVirtual spreads the virtual shards of a class without partitioning evenly across
+// its physical shards. Virtual shards stay on their physical shard unless it doesn't exist anymore
+// or owns more than its share, so that as few objects as possible are routed to another shard.
+// It returns the sorted names of the physical shards whose virtual shards changed.
+// The caller must hold the write lock.
+func (m *metaClass) distributeVirtual() []string {
+	st := &m.Sharding
+	if st.PartitioningEnabled || len(st.Virtual) == 0 || len(st.Physical) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(st.Physical))
+	for name := range st.Physical {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	target := make(map[string]int, len(names))
+	for i, name := range names {
+		target[name] = len(st.Virtual) / len(names)
+		if i < len(st.Virtual)%len(names) {
+			target[name]++
+		}
+	}
+
+	owned := make(map[string][]int, len(names))
+	var pool []int // virtual shards which must be assigned to another physical shard
+	for i, vs := range st.Virtual {
+		if n, ok := target[vs.AssignedToPhysical]; ok && len(owned[vs.AssignedToPhysical]) < n {
+			owned[vs.AssignedToPhysical] = append(owned[vs.AssignedToPhysical], i)
+		} else {
+			pool = append(pool, i)
+		}
+	}
+	for _, name := range names {
+		for len(owned[name]) < target[name] && len(pool) > 0 {
+			st.Virtual[pool[0]].AssignedToPhysical = name
+			owned[name] = append(owned[name], pool[0])
+			pool = pool[1:]
+		}
+	}
+
+	var changed []string
+	for _, name := range names {
+		sort.Ints(owned[name])
+		ownsVirtual := make([]string, len(owned[name]))
+		percentage := 0.0
+		for i, idx := range owned[name] {
+			ownsVirtual[i] = st.Virtual[idx].Name
+			percentage += st.Virtual[idx].OwnsPercentage
+		}
+		p := st.Physical[name]
+		if slices.Equal(p.OwnsVirtual, ownsVirtual) && p.OwnsPercentage == percentage {
+			continue
+		}
+		p = p.DeepCopy()
+		p.OwnsVirtual = ownsVirtual
+		p.OwnsPercentage = percentage
+		st.Physical[name] = p
+		changed = append(changed, name)
+	}
+	return changed
+}
+
+// validateShardNodes checks that nodes is a non-empty list of distinct nodes
+func validateShardNodes(name string, nodes []string) error {
+	if len(nodes) == 0 {
+		return fmt.Errorf("shard %q: empty node list", name)
+	}
+	seen := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		if _, ok := seen[node]; ok {
+			return fmt.Errorf("shard %q: duplicate node %q", name, node)
+		}
+		seen[node] = struct{}{}
+	}
+	return nil
+}
+
+// RemoveShard removes the shard name. The last shard of a class without partitioning
+// can't be removed. Otherwise the virtual shards of the removed shard are reassigned
+// to the remaining shards.
+func (m *metaClass) RemoveShard(name string, v uint64, modifiedAt time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	if _, ok := m.Sharding.Physical[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrShardNotFound, name)
+	}
+	if !m.Sharding.PartitioningEnabled && len(m.Sharding.Physical) == 1 {
+		return fmt.Errorf("%w: %s", ErrLastShard, name)
+	}
+	m.Sharding.DeletePartition(name)
+	m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: name, Type: TenantRemoved, Version: v})
+	m.ShardVersion = v
+	for _, changed := range m.distributeVirtual() {
+		m.setShard(changed, m.Sharding.Physical[changed], v, modifiedAt)
+	}
+	return nil
+}
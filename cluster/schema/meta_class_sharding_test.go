@@ -0,0 +1,160 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+	shardingcfg "github.com/weaviate/weaviate/usecases/sharding/config"
+)
+
+func TestMetaClassShardingConfig(t *testing.T) {
+	cfg := shardingcfg.Config{VirtualPerPhysical: 128, DesiredCount: 2, Key: shardingcfg.DefaultKey}
+	m := &metaClass{Sharding: sharding.State{Config: cfg}}
+
+	got := m.ShardingConfig()
+	assert.Equal(t, cfg, got)
+	got.DesiredCount = 5
+	assert.Equal(t, 2, m.Sharding.Config.DesiredCount)
+}
+
+func TestMetaClassVirtualShardCount(t *testing.T) {
+	m := &metaClass{}
+	assert.Equal(t, shardingcfg.DefaultVirtualPerPhysical, m.VirtualShardCount())
+
+	m.Sharding.Config.VirtualPerPhysical = 16
+	assert.Equal(t, 16, m.VirtualShardCount())
+}
+
+func TestMetaClassShardCountDrift(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{
+		Config: shardingcfg.Config{DesiredCount: 3},
+		Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1"},
+			"S2": {Name: "S2"},
+		},
+	}}
+	configured, actual, delta := m.ShardCountDrift()
+	assert.Equal(t, 3, configured)
+	assert.Equal(t, 2, actual)
+	assert.Equal(t, -1, delta)
+}
+
+func TestMetaClassAddShard(t *testing.T) {
+	m := &metaClass{}
+	nodes := []string{"A", "B"}
+	req, err := m.AddShard("A", "S1", nodes, models.TenantActivityStatusHOT, 1, time.UnixMilli(2))
+	require.Nil(t, err)
+	assert.Equal(t, []*command.Tenant{{Name: "S1", Status: models.TenantActivityStatusHOT}}, req.Tenants)
+	nodes[0] = "C"
+	assert.Equal(t, []string{"A", "B"}, m.Sharding.Physical["S1"].BelongsToNodes)
+	assert.Equal(t, models.TenantActivityStatusHOT, m.Sharding.Physical["S1"].Status)
+	assert.Equal(t, int64(2), m.Sharding.Physical["S1"].LastModifiedUnix)
+
+	_, err = m.AddShard("A", "S1", []string{"C"}, "", 2, time.Time{})
+	assert.ErrorIs(t, err, ErrShardExists)
+	_, err = m.AddShard("A", "S2", nil, "", 2, time.Time{})
+	assert.NotNil(t, err)
+	_, err = m.AddShard("A", "S2", []string{"A", "B", "A"}, "", 2, time.Time{})
+	assert.NotNil(t, err)
+	assert.Len(t, m.Sharding.Physical, 1)
+
+	req, err = m.AddShard("A", "S2", []string{"C"}, models.TenantActivityStatusHOT, 3, time.Time{})
+	require.Nil(t, err)
+	assert.Empty(t, req.Tenants, "S2 belongs to another node")
+
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "S1", Type: TenantAdded, Status: models.TenantActivityStatusHOT, Version: 1},
+		{Tenant: "S2", Type: TenantAdded, Status: models.TenantActivityStatusHOT, Version: 3},
+	}, changes)
+}
+
+func TestMetaClassAddShardDistributesVirtual(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{
+		Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A"}, OwnsVirtual: []string{"V1", "V2", "V3", "V4"}, OwnsPercentage: 1.0},
+		},
+		Virtual: []sharding.Virtual{
+			{Name: "V1", AssignedToPhysical: "S1", OwnsPercentage: 0.25},
+			{Name: "V2", AssignedToPhysical: "S1", OwnsPercentage: 0.25},
+			{Name: "V3", AssignedToPhysical: "S1", OwnsPercentage: 0.25},
+			{Name: "V4", AssignedToPhysical: "S1", OwnsPercentage: 0.25},
+		},
+	}}
+
+	_, err := m.AddShard("A", "S2", []string{"B"}, models.TenantActivityStatusHOT, 1, time.Time{})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"V1", "V2"}, m.Sharding.Physical["S1"].OwnsVirtual)
+	assert.Equal(t, []string{"V3", "V4"}, m.Sharding.Physical["S2"].OwnsVirtual)
+	assert.Equal(t, 0.5, m.Sharding.Physical["S1"].OwnsPercentage)
+	assert.Equal(t, 0.5, m.Sharding.Physical["S2"].OwnsPercentage)
+	assert.Equal(t, "S2", m.Sharding.Virtual[3].AssignedToPhysical)
+
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "S2", Type: TenantAdded, Status: models.TenantActivityStatusHOT, Version: 1},
+		{Tenant: "S1", Type: TenantUpdated, Version: 1},
+	}, changes)
+}
+
+func TestMetaClassRemoveShard(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A"}},
+		"S2": {Name: "S2", BelongsToNodes: []string{"B"}},
+	}}}
+	assert.ErrorIs(t, m.RemoveShard("S3", 1, time.Time{}), ErrShardNotFound)
+	require.Nil(t, m.RemoveShard("S1", 2, time.Time{}))
+	assert.NotContains(t, m.Sharding.Physical, "S1")
+	assert.ErrorIs(t, m.RemoveShard("S2", 3, time.Time{}), ErrLastShard)
+	assert.Contains(t, m.Sharding.Physical, "S2")
+
+	m.Sharding.PartitioningEnabled = true
+	require.Nil(t, m.RemoveShard("S2", 4, time.Time{}))
+	assert.Empty(t, m.Sharding.Physical)
+
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "S1", Type: TenantRemoved, Version: 2},
+		{Tenant: "S2", Type: TenantRemoved, Version: 4},
+	}, changes)
+}
+
+func TestMetaClassRemoveShardReassignsVirtual(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{
+		Physical: map[string]sharding.Physical{
+			"S1": {Name: "S1", BelongsToNodes: []string{"A"}, OwnsVirtual: []string{"V1"}, OwnsPercentage: 0.25},
+			"S2": {Name: "S2", BelongsToNodes: []string{"B"}, OwnsVirtual: []string{"V2", "V3"}, OwnsPercentage: 0.5},
+			"S3": {Name: "S3", BelongsToNodes: []string{"C"}, OwnsVirtual: []string{"V4"}, OwnsPercentage: 0.25},
+		},
+		Virtual: []sharding.Virtual{
+			{Name: "V1", AssignedToPhysical: "S1", OwnsPercentage: 0.25},
+			{Name: "V2", AssignedToPhysical: "S2", OwnsPercentage: 0.25},
+			{Name: "V3", AssignedToPhysical: "S2", OwnsPercentage: 0.25},
+			{Name: "V4", AssignedToPhysical: "S3", OwnsPercentage: 0.25},
+		},
+	}}
+
+	require.Nil(t, m.RemoveShard("S2", 1, time.Time{}))
+	for _, vs := range m.Sharding.Virtual {
+		assert.Contains(t, m.Sharding.Physical, vs.AssignedToPhysical, "virtual shard %s is orphaned", vs.Name)
+	}
+	assert.Equal(t, []string{"V1", "V2"}, m.Sharding.Physical["S1"].OwnsVirtual)
+	assert.Equal(t, []string{"V3", "V4"}, m.Sharding.Physical["S3"].OwnsVirtual)
+	assert.Equal(t, 0.5, m.Sharding.Physical["S1"].OwnsPercentage)
+}
@@ -0,0 +1,97 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+// CopyStatsBuckets are the upper bounds of the CopyShardingState duration histogram.
+// Copies taking longer than the last bound are counted in an additional bucket.
+var CopyStatsBuckets = [...]time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+}
+
+// CopyStats summarizes the cost of CopyShardingState calls
+type CopyStats struct {
+	Count         uint64
+	TotalDuration time.Duration
+	// TotalBytes is the approximate amount of memory allocated by all copies
+	TotalBytes uint64
+	// Buckets[i] counts the copies which took at most CopyStatsBuckets[i].
+	// The last element counts the copies exceeding all bounds.
+	Buckets [len(CopyStatsBuckets) + 1]uint64
+}
+
+type copyStats struct {
+	enabled    atomic.Bool
+	count      atomic.Uint64
+	totalNanos atomic.Uint64
+	totalBytes atomic.Uint64
+	buckets    [len(CopyStatsBuckets) + 1]atomic.Uint64
+}
+
+func (s *copyStats) observe(d time.Duration, size uint64) {
+	s.count.Add(1)
+	s.totalNanos.Add(uint64(d))
+	s.totalBytes.Add(size)
+	i := 0
+	for i < len(CopyStatsBuckets) && d > CopyStatsBuckets[i] {
+		i++
+	}
+	s.buckets[i].Add(1)
+}
+
+// EnableCopyStats turns the recording of CopyShardingState statistics on or off.
+// Recording is disabled by default to avoid any overhead.
+func (m *metaClass) EnableCopyStats(enabled bool) {
+	m.copyStats.enabled.Store(enabled)
+}
+
+// CopyStats returns the statistics recorded for CopyShardingState so far
+func (m *metaClass) CopyStats() CopyStats {
+	cs := CopyStats{
+		Count:         m.copyStats.count.Load(),
+		TotalDuration: time.Duration(m.copyStats.totalNanos.Load()),
+		TotalBytes:    m.copyStats.totalBytes.Load(),
+	}
+	for i := range cs.Buckets {
+		cs.Buckets[i] = m.copyStats.buckets[i].Load()
+	}
+	return cs
+}
+
+// approxStateSize estimates the memory held by st
+func approxStateSize(st *sharding.State) uint64 {
+	size := uint64(unsafe.Sizeof(*st))
+	for name, p := range st.Physical {
+		size += uint64(unsafe.Sizeof(p)) + uint64(len(name)+len(p.Name)+len(p.Status))
+		for _, v := range p.OwnsVirtual {
+			size += uint64(unsafe.Sizeof(v)) + uint64(len(v))
+		}
+		for _, n := range p.BelongsToNodes {
+			size += uint64(unsafe.Sizeof(n)) + uint64(len(n))
+		}
+	}
+	for _, v := range st.Virtual {
+		size += uint64(unsafe.Sizeof(v)) + uint64(len(v.Name)+len(v.AssignedToPhysical))
+	}
+	return size
+}
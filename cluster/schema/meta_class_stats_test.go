@@ -0,0 +1,67 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassCopyStats(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"S1": {Name: "S1", BelongsToNodes: []string{"A", "B"}},
+	}}}
+
+	// disabled by default
+	m.CopyShardingState()
+	assert.Equal(t, CopyStats{}, m.CopyStats())
+
+	m.EnableCopyStats(true)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.CopyShardingState()
+		}()
+	}
+	wg.Wait()
+
+	stats := m.CopyStats()
+	assert.Equal(t, uint64(10), stats.Count)
+	assert.Positive(t, stats.TotalBytes)
+	var total uint64
+	for _, c := range stats.Buckets {
+		total += c
+	}
+	assert.Equal(t, stats.Count, total)
+
+	m.EnableCopyStats(false)
+	m.CopyShardingState()
+	assert.Equal(t, uint64(10), m.CopyStats().Count)
+}
+
+func TestMetaClassNextSequence(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{}}}
+	assert.Equal(t, uint64(1), m.NextSequence())
+	assert.Equal(t, uint64(2), m.NextSequence())
+
+	// mutations increment the sequence as well
+	assert.Nil(t, m.LockGuard(func(*metaClass) error { return nil }))
+	assert.Nil(t, m.DeleteTenants(&command.DeleteTenantsRequest{}, 1, time.Time{}))
+	assert.Equal(t, uint64(5), m.NextSequence())
+}
@@ -0,0 +1,597 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/cluster/types"
+	enterrors "github.com/weaviate/weaviate/entities/errors"
+	"github.com/weaviate/weaviate/entities/models"
+	entSchema "github.com/weaviate/weaviate/entities/schema"
+	"github.com/weaviate/weaviate/usecases/sharding"
+	"golang.org/x/exp/slices"
+)
+
+// TenantStatus is the activity status of a tenant
+type TenantStatus string
+
+const (
+	TenantStatusHot    = TenantStatus(models.TenantActivityStatusHOT)
+	TenantStatusCold   = TenantStatus(models.TenantActivityStatusCOLD)
+	TenantStatusFrozen = TenantStatus(models.TenantActivityStatusFROZEN)
+	// TenantStatusFreezing and TenantStatusUnfreezing are transient statuses used while (un)offloading a tenant
+	TenantStatusFreezing   = TenantStatus(types.TenantActivityStatusFREEZING)
+	TenantStatusUnfreezing = TenantStatus(types.TenantActivityStatusUNFREEZING)
+)
+
+// knownTenantStatuses maps the activity statuses a tenant can be in to their TenantStatus
+var knownTenantStatuses = map[string]TenantStatus{
+	models.TenantActivityStatusHOT:       TenantStatusHot,
+	models.TenantActivityStatusCOLD:      TenantStatusCold,
+	models.TenantActivityStatusFROZEN:    TenantStatusFrozen,
+	types.TenantActivityStatusFREEZING:   TenantStatusFreezing,
+	types.TenantActivityStatusUNFREEZING: TenantStatusUnfreezing,
+}
+
+func isKnownTenantStatus(status string) bool {
+	_, ok := knownTenantStatuses[status]
+	return ok
+}
+
+// TenantStatusTyped returns the activity status of tenant as TenantStatus.
+// It returns ErrUnknownTenantStatus if the stored status isn't a known status.
+func (m *metaClass) TenantStatusTyped(tenant string) (TenantStatus, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	p, ok := m.Sharding.Physical[m.tenantName(tenant)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrShardNotFound, tenant)
+	}
+	status, ok := knownTenantStatuses[p.ActivityStatus()]
+	if !ok {
+		return "", fmt.Errorf("%w: tenant %s has status %q", ErrUnknownTenantStatus, tenant, p.Status)
+	}
+	return status, nil
+}
+
+// inactiveTenantStatuses is the set of statuses of tenants which store data but don't serve queries
+var inactiveTenantStatuses = map[string]struct{}{
+	models.TenantActivityStatusCOLD:   {},
+	models.TenantActivityStatusFROZEN: {},
+}
+
+func isInactiveTenantStatus(status string) bool {
+	_, ok := inactiveTenantStatuses[status]
+	return ok
+}
+
+// tenantName returns the name under which the specified tenant is stored
+func (m *metaClass) tenantName(name string) string {
+	return entSchema.NormalizeTenantName(&m.Class, name)
+}
+
+// normalizeTenantNames normalizes the names of the requested tenants in place
+// if tenant name normalization is enabled.
+// It fails if two tenants share the same normalized name, leaving tenants unchanged.
+func (m *metaClass) normalizeTenantNames(tenants []*command.Tenant) error {
+	if !entSchema.TenantNameNormalizationEnabled(&m.Class) {
+		return nil
+	}
+	seen := make(map[string]string, len(tenants))
+	for _, t := range tenants {
+		name := strings.ToLower(t.Name)
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("tenants %q and %q have the same normalized name %q", other, t.Name, name)
+		}
+		seen[name] = t.Name
+	}
+	for _, t := range tenants {
+		t.Name = strings.ToLower(t.Name)
+	}
+	return nil
+}
+
+// CaseInsensitiveCollisions returns the tenants whose names only differ in case,
+// keyed by the lower cased name and mapped to the sorted names of the colliding tenants
+func (m *metaClass) CaseInsensitiveCollisions() map[string][]string {
+	m.RLock()
+	defer m.RUnlock()
+
+	variants := make(map[string][]string, len(m.Sharding.Physical))
+	for name := range m.Sharding.Physical {
+		lower := strings.ToLower(name)
+		variants[lower] = append(variants[lower], name)
+	}
+	for lower, names := range variants {
+		if len(names) < 2 {
+			delete(variants, lower)
+			continue
+		}
+		sort.Strings(names)
+	}
+	return variants
+}
+
+// HotTenantCount returns the number of HOT tenants
+func (m *metaClass) HotTenantCount() int {
+	if m == nil {
+		return 0
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+
+	n := 0
+	for _, p := range m.Sharding.Physical {
+		if p.ActivityStatus() == models.TenantActivityStatusHOT {
+			n++
+		}
+	}
+	return n
+}
+
+// FrozenTenantsOnNode returns the sorted names of the FROZEN tenants still owned by node
+func (m *metaClass) FrozenTenantsOnNode(node string) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if p.ActivityStatus() == models.TenantActivityStatusFROZEN && slices.Contains(p.BelongsToNodes, node) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// IdleTenants returns the sorted names of the HOT tenants whose last activity in lastActivity
+// is more than idleFor before now. Tenants without a last activity are considered idle.
+func (m *metaClass) IdleTenants(lastActivity map[string]time.Time, idleFor time.Duration, now time.Time) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if p.ActivityStatus() != models.TenantActivityStatusHOT {
+			continue
+		}
+		if last, ok := lastActivity[name]; !ok || now.Sub(last) > idleFor {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// InactiveTenants returns the sorted names of the tenants which are COLD or FROZEN
+func (m *metaClass) InactiveTenants() []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if isInactiveTenantStatus(p.ActivityStatus()) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// TenantsOnNodeWithStatus returns the sorted names of the tenants owned by node whose activity status is status
+func (m *metaClass) TenantsOnNodeWithStatus(node, status string) []string {
+	res := make([]string, 0)
+	if m == nil {
+		return res
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+
+	for name, p := range m.Sharding.Physical {
+		if p.ActivityStatus() == status && slices.Contains(p.BelongsToNodes, node) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// TenantsMissingPhysical returns the sorted names of the expected tenants without a physical shard
+func (m *metaClass) TenantsMissingPhysical(expected []string) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for _, name := range expected {
+		if _, ok := m.Sharding.Physical[m.tenantName(name)]; !ok {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return slices.Compact(res)
+}
+
+// StreamTenants sends the sorted names of all tenants on the returned channel, which is closed
+// once all names were sent or ctx is done. The names are taken under the read lock at the time
+// of the call, the lock isn't held while streaming.
+func (m *metaClass) StreamTenants(ctx context.Context) <-chan string {
+	m.RLock()
+	names := make([]string, 0, len(m.Sharding.Physical))
+	for name := range m.Sharding.Physical {
+		names = append(names, name)
+	}
+	m.RUnlock()
+	sort.Strings(names)
+
+	ch := make(chan string)
+	enterrors.GoWrapper(func() {
+		defer close(ch)
+		for _, name := range names {
+			select {
+			case ch <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}, logrus.StandardLogger())
+	return ch
+}
+
+// TenantLastModified returns the time tenant was created or its status last changed.
+// Tenants without a recorded modification time return the creation time of the class.
+func (m *metaClass) TenantLastModified(tenant string) (time.Time, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	p, ok := m.Sharding.Physical[m.tenantName(tenant)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrShardNotFound, tenant)
+	}
+	if p.LastModifiedUnix == 0 {
+		return m.CreationTime, nil
+	}
+	return time.UnixMilli(p.LastModifiedUnix), nil
+}
+
+// LastStatusChangeReason returns the reason of the most recent entry of the status history of tenant,
+// which is empty if the change was made without a reason or if there is no history
+func (m *metaClass) LastStatusChangeReason(tenant string) (string, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	p, ok := m.Sharding.Physical[m.tenantName(tenant)]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrShardNotFound, tenant)
+	}
+	if len(p.StatusHistory) == 0 {
+		return "", nil
+	}
+	return p.StatusHistory[len(p.StatusHistory)-1].Reason, nil
+}
+
+// TenantsModifiedAfter returns the sorted names of the tenants whose last modification time is after t
+func (m *metaClass) TenantsModifiedAfter(t time.Time) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if p.LastModifiedUnix != 0 && time.UnixMilli(p.LastModifiedUnix).After(t) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// InvalidStatusTenants returns the tenants whose activity status is not a known status
+// mapped to their stored status
+func (m *metaClass) InvalidStatusTenants() map[string]string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make(map[string]string)
+	for name, p := range m.Sharding.Physical {
+		if !isKnownTenantStatus(p.ActivityStatus()) {
+			res[name] = p.Status
+		}
+	}
+	return res
+}
+
+// SetStatusForNodeTenants sets the activity status of every tenant whose primary owner is node like UpdateTenants.
+// Tenants which are already in the requested status are skipped, tenants whose transition isn't allowed are
+// reported in a TenantsError. It returns the request to be applied by the DB of nodeID.
+func (m *metaClass) SetStatusForNodeTenants(nodeID, node, status string, v uint64, modifiedAt time.Time) (*command.UpdateTenantsRequest, error) {
+	if !isKnownTenantStatus(status) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTenantStatus, status)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	req := &command.UpdateTenantsRequest{}
+	for name, p := range m.Sharding.Physical {
+		if len(p.BelongsToNodes) == 0 || p.BelongsToNodes[0] != node || p.ActivityStatus() == status {
+			continue
+		}
+		req.Tenants = append(req.Tenants, &command.Tenant{Name: name, Status: status})
+	}
+	if len(req.Tenants) == 0 {
+		return req, nil
+	}
+	sort.Slice(req.Tenants, func(i, j int) bool { return req.Tenants[i].Name < req.Tenants[j].Name })
+	return req, m.updateTenants(nodeID, req, v, modifiedAt)
+}
+
+// freezable returns true if p can be frozen through UpdateTenants and isn't frozen or being frozen already
+func (m *metaClass) freezable(p sharding.Physical) bool {
+	noop, reason := m.checkStatusTransition(p, models.TenantActivityStatusFROZEN)
+	return !noop && reason == ""
+}
+
+// FreezeCandidates returns the sorted names of the specified tenants which can be frozen and the
+// names of the tenants which don't exist. Tenants which are already frozen or being frozen are skipped.
+// The candidates are frozen by updating them to FROZEN through UpdateTenants.
+func (m *metaClass) FreezeCandidates(names []string) (tenants, missing []string) {
+	m.RLock()
+	defer m.RUnlock()
+
+	for _, name := range names {
+		p, ok := m.Sharding.Physical[m.tenantName(name)]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		if m.freezable(p) {
+			tenants = append(tenants, p.Name)
+		}
+	}
+	sort.Strings(tenants)
+	return slices.Compact(tenants), missing
+}
+
+// FreezeCandidatesOnNode returns the sorted names of the tenants whose primary owner is node
+// which can be frozen. Tenants which are already frozen or being frozen are skipped.
+func (m *metaClass) FreezeCandidatesOnNode(node string) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	tenants := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if len(p.BelongsToNodes) > 0 && p.BelongsToNodes[0] == node && m.freezable(p) {
+			tenants = append(tenants, name)
+		}
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// FreezeCandidatesByLabel returns the sorted names of the tenants whose metadata maps key to value
+// which can be frozen. Tenants which are already frozen or being frozen are skipped.
+func (m *metaClass) FreezeCandidatesByLabel(key, value string) []string {
+	m.RLock()
+	defer m.RUnlock()
+
+	tenants := make([]string, 0)
+	for name, p := range m.Sharding.Physical {
+		if l, ok := p.Metadata[key]; ok && l == value && m.freezable(p) {
+			tenants = append(tenants, name)
+		}
+	}
+	sort.Strings(tenants)
+	return tenants
+}
+
+// SetTenantNodes replaces the nodes of tenant by nodes, which must be a non-empty list of distinct nodes.
+// Setting the nodes the tenant already belongs to is a no-op.
+func (m *metaClass) SetTenantNodes(tenant string, nodes []string, v uint64, modifiedAt time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	name := m.tenantName(tenant)
+	p, ok := m.Sharding.Physical[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrShardNotFound, tenant)
+	}
+	if err := validateShardNodes(name, nodes); err != nil {
+		return err
+	}
+	if slices.Equal(p.BelongsToNodes, nodes) {
+		return nil
+	}
+	p = p.DeepCopy()
+	p.BelongsToNodes = slices.Clone(nodes)
+	p.LastModifiedUnix = unixMilli(modifiedAt)
+	m.Sharding.Physical[name] = p
+	m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: name, Type: TenantUpdated, Status: p.Status, Version: v})
+	m.ShardVersion = v
+	return nil
+}
+
+// SnapshotStatuses returns the status of every tenant
+func (m *metaClass) SnapshotStatuses() map[string]string {
+	m.RLock()
+	defer m.RUnlock()
+
+	res := make(map[string]string, len(m.Sharding.Physical))
+	for name, p := range m.Sharding.Physical {
+		res[name] = p.ActivityStatus()
+	}
+	return res
+}
+
+// RestoreStatusesRequest returns the request setting the status of every tenant of snapshot to its
+// status in snapshot, to be applied through UpdateTenants. Tenants which don't exist anymore and
+// tenants which already have their status in snapshot are skipped.
+// It fails if snapshot contains an unknown status.
+func (m *metaClass) RestoreStatusesRequest(snapshot map[string]string) (*command.UpdateTenantsRequest, error) {
+	var invalid []string
+	for name, status := range snapshot {
+		if !isKnownTenantStatus(status) {
+			invalid = append(invalid, name)
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, fmt.Errorf("%w: tenants %v: unknown status in snapshot", ErrUnknownTenantStatus, invalid)
+	}
+
+	m.RLock()
+	defer m.RUnlock()
+
+	req := &command.UpdateTenantsRequest{}
+	for name, status := range snapshot {
+		if p, ok := m.Sharding.Physical[name]; ok && p.ActivityStatus() != status {
+			req.Tenants = append(req.Tenants, &command.Tenant{Name: name, Status: status})
+		}
+	}
+	sort.Slice(req.Tenants, func(i, j int) bool { return req.Tenants[i].Name < req.Tenants[j].Name })
+	return req, nil
+}
+
+// SetMaxTenantDeletes sets the maximum number of tenants a single delete request may delete.
+// A limit of 0 removes the limit.
+// It is applied through the TYPE_SET_MAX_TENANT_DELETES command only, since DeleteTenants enforces it.
+func (m *metaClass) SetMaxTenantDeletes(limit int) error {
+	if limit < 0 {
+		return fmt.Errorf("negative tenant deletion limit: %d", limit)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+	m.MaxTenantDeletes = limit
+	return nil
+}
+
+// ValidateDelete checks req before it is applied. It returns the sorted names of the HOT tenants
+// it would delete as warnings, and fails with ErrTooManyTenantDeletes if it would delete more
+// existing tenants than allowed by SetMaxTenantDeletes.
+func (m *metaClass) ValidateDelete(req *command.DeleteTenantsRequest) (warnings []string, err error) {
+	m.RLock()
+	defer m.RUnlock()
+	return m.checkTenantDeletes(req)
+}
+
+// checkTenantDeletes implements ValidateDelete, the caller must hold the lock
+func (m *metaClass) checkTenantDeletes(req *command.DeleteTenantsRequest) (warnings []string, err error) {
+	deleted := make(map[string]struct{}, len(req.Tenants))
+	for _, tenant := range req.Tenants {
+		name := m.tenantName(tenant)
+		p, ok := m.Sharding.Physical[name]
+		if !ok {
+			continue
+		}
+		if _, ok := deleted[name]; ok {
+			continue
+		}
+		deleted[name] = struct{}{}
+		if p.ActivityStatus() == models.TenantActivityStatusHOT {
+			warnings = append(warnings, name)
+		}
+	}
+	sort.Strings(warnings)
+	if m.MaxTenantDeletes > 0 && len(deleted) > m.MaxTenantDeletes {
+		return warnings, fmt.Errorf("%w: %d tenants, limit is %d", ErrTooManyTenantDeletes, len(deleted), m.MaxTenantDeletes)
+	}
+	return warnings, nil
+}
+
+// MergeTenantMetadata sets the labels of updates on the metadata of their tenant, keeping the
+// labels which aren't part of the update. Tenants whose labels don't change are skipped,
+// tenants which don't exist are reported in a TenantsError.
+func (m *metaClass) MergeTenantMetadata(updates map[string]map[string]string, v uint64, modifiedAt time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	tenants := make([]string, 0, len(updates))
+	for tenant := range updates {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+
+	rejected := TenantsError{}
+	for _, tenant := range tenants {
+		labels := updates[tenant]
+		name := m.tenantName(tenant)
+		p, ok := m.Sharding.Physical[name]
+		if !ok {
+			rejected[tenant] = ErrShardNotFound
+			continue
+		}
+		changed := false
+		for k, v := range labels {
+			if l, ok := p.Metadata[k]; !ok || l != v {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			continue
+		}
+		p = p.DeepCopy()
+		if p.Metadata == nil {
+			p.Metadata = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			p.Metadata[k] = v
+		}
+		p.LastModifiedUnix = unixMilli(modifiedAt)
+		m.Sharding.Physical[name] = p
+		m.tenantChanges.record(m.ShardVersion, TenantChange{Tenant: name, Type: TenantUpdated, Status: p.Status, Version: v})
+	}
+	m.ShardVersion = v
+
+	if len(rejected) > 0 {
+		return rejected
+	}
+	return nil
+}
+
+// validTenantPlacement returns true if nodes consists of exactly replFactor distinct nodes.
+// Any placement is valid if replFactor is not set.
+// It validates the nodes of new tenants, see AddTenants and TenantBatch.
+func validTenantPlacement(nodes []string, replFactor int64) bool {
+	if replFactor <= 0 {
+		return true
+	}
+	distinct := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		distinct[node] = struct{}{}
+	}
+	return len(nodes) == len(distinct) && int64(len(nodes)) == replFactor
+}
+
+// SuspendTenantCreation blocks or unblocks the creation of new tenants.
+// While suspended, AddTenants fails with ErrTenantCreationSuspended; existing tenants
+// can still be updated and deleted.
+func (m *metaClass) SuspendTenantCreation(suspend bool) {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	m.TenantCreationSuspended = suspend
+}
@@ -0,0 +1,731 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/cluster/types"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassInvalidStatusTenants(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2"},
+		"T3": {Name: "T3", Status: "WARM"},
+		"T4": {Name: "T4", Status: models.TenantActivityStatusFROZEN},
+		"T5": {Name: "T5", Status: "hot"},
+	}}}
+	assert.Equal(t, map[string]string{"T3": "WARM", "T5": "hot"}, m.InvalidStatusTenants())
+}
+
+func TestMetaClassHotTenantCount(t *testing.T) {
+	var nilMeta *metaClass
+	assert.Equal(t, 0, nilMeta.HotTenantCount())
+
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", Status: models.TenantActivityStatusCOLD},
+		"T3": {Name: "T3"},
+		"T4": {Name: "T4", Status: models.TenantActivityStatusFROZEN},
+	}}}
+	assert.Equal(t, 2, m.HotTenantCount())
+}
+
+func TestMetaClassFrozenTenantsOnNode(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusFROZEN},
+		"T2": {Name: "T2", BelongsToNodes: []string{"B", "A"}, Status: models.TenantActivityStatusFROZEN},
+		"T3": {Name: "T3", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusCOLD},
+		"T4": {Name: "T4", BelongsToNodes: []string{"C"}, Status: models.TenantActivityStatusFROZEN},
+	}}}
+	assert.Equal(t, []string{"T1", "T2"}, m.FrozenTenantsOnNode("A"))
+	assert.Empty(t, m.FrozenTenantsOnNode("D"))
+}
+
+func TestMetaClassIdleTenants(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", Status: models.TenantActivityStatusHOT},
+		"T3": {Name: "T3", Status: models.TenantActivityStatusHOT},
+		"T4": {Name: "T4", Status: models.TenantActivityStatusCOLD},
+		"T5": {Name: "T5"},
+	}}}
+	lastActivity := map[string]time.Time{
+		"T1": now.Add(-time.Minute),
+		"T2": now.Add(-time.Hour),
+		"T4": now.Add(-time.Hour),
+		"T5": now.Add(-10 * time.Minute),
+	}
+	assert.Equal(t, []string{"T2", "T3"}, m.IdleTenants(lastActivity, 10*time.Minute, now))
+	assert.Equal(t, []string{"T1", "T2", "T3", "T5"}, m.IdleTenants(lastActivity, 0, now))
+}
+
+func TestMetaClassInactiveTenants(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", Status: models.TenantActivityStatusFROZEN},
+		"T3": {Name: "T3"},
+		"T4": {Name: "T4", Status: models.TenantActivityStatusCOLD},
+	}}}
+	assert.Equal(t, []string{"T2", "T4"}, m.InactiveTenants())
+}
+
+func TestMetaClassTenantStatusTyped(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", Status: models.TenantActivityStatusCOLD},
+		"T2": {Name: "T2", Status: types.TenantActivityStatusFREEZING},
+		"T3": {Name: "T3", Status: "WARM"},
+	}}}
+
+	status, err := m.TenantStatusTyped("T1")
+	require.Nil(t, err)
+	assert.Equal(t, TenantStatusCold, status)
+	status, err = m.TenantStatusTyped("T2")
+	require.Nil(t, err)
+	assert.Equal(t, TenantStatusFreezing, status)
+
+	_, err = m.TenantStatusTyped("T3")
+	assert.ErrorIs(t, err, ErrUnknownTenantStatus)
+	_, err = m.TenantStatusTyped("T4")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+}
+
+func TestMetaClassTenantsOnNodeWithStatus(t *testing.T) {
+	var nilMeta *metaClass
+	assert.Empty(t, nilMeta.TenantsOnNodeWithStatus("A", models.TenantActivityStatusHOT))
+
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", BelongsToNodes: []string{"B"}, Status: models.TenantActivityStatusHOT},
+		"T3": {Name: "T3", BelongsToNodes: []string{"B", "A"}, Status: models.TenantActivityStatusCOLD},
+		"T0": {Name: "T0", BelongsToNodes: []string{"A"}},
+	}}}
+	assert.Equal(t, []string{"T0", "T1"}, m.TenantsOnNodeWithStatus("A", models.TenantActivityStatusHOT))
+	assert.Equal(t, []string{"T3"}, m.TenantsOnNodeWithStatus("A", models.TenantActivityStatusCOLD))
+	assert.Empty(t, m.TenantsOnNodeWithStatus("C", models.TenantActivityStatusHOT))
+}
+
+func TestMetaClassTenantsMissingPhysical(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}},
+		"T2": {Name: "T2", BelongsToNodes: []string{"B"}},
+	}}}
+	assert.Empty(t, m.TenantsMissingPhysical(nil))
+	assert.Empty(t, m.TenantsMissingPhysical([]string{"T2", "T1"}))
+	assert.Equal(t, []string{"T0", "T3"}, m.TenantsMissingPhysical([]string{"T3", "T1", "T0", "T3"}))
+}
+
+func TestMetaClassSetStatusForNodeTenants(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusCOLD},
+		"T3": {Name: "T3", BelongsToNodes: []string{"B", "A"}, Status: models.TenantActivityStatusHOT},
+		"T4": {Name: "T4", BelongsToNodes: []string{"A"}},
+	}}}
+
+	_, err := m.SetStatusForNodeTenants("A", "A", "WARM", 1, time.Time{})
+	assert.ErrorIs(t, err, ErrUnknownTenantStatus)
+
+	req, err := m.SetStatusForNodeTenants("B", "A", models.TenantActivityStatusCOLD, 2, time.UnixMilli(5))
+	require.Nil(t, err)
+	assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T1"].Status)
+	assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T4"].Status)
+	assert.Equal(t, models.TenantActivityStatusHOT, m.Sharding.Physical["T3"].Status)
+	assert.Equal(t, int64(5), m.Sharding.Physical["T1"].LastModifiedUnix)
+	assert.Equal(t, uint64(2), m.ShardVersion)
+
+	// only T1 is stored on B
+	require.Len(t, req.Tenants, 1)
+	assert.Equal(t, "T1", req.Tenants[0].Name)
+
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "T1", Type: TenantUpdated, Status: models.TenantActivityStatusCOLD, Version: 2},
+		{Tenant: "T4", Type: TenantUpdated, Status: models.TenantActivityStatusCOLD, Version: 2},
+	}, changes)
+}
+
+func TestMetaClassTenantNameNormalization(t *testing.T) {
+	newMetaClass := func(normalize bool) *metaClass {
+		return &metaClass{
+			Class: models.Class{
+				Class:              "C",
+				MultiTenancyConfig: &models.MultiTenancyConfig{Enabled: true, NormalizeTenantNames: normalize},
+			},
+			Sharding: sharding.State{Physical: map[string]sharding.Physical{}, PartitioningEnabled: true},
+		}
+	}
+	addReq := func(names ...string) *command.AddTenantsRequest {
+		req := &command.AddTenantsRequest{ClusterNodes: []string{"A"}}
+		for _, name := range names {
+			req.Tenants = append(req.Tenants, &command.Tenant{Name: name, Status: models.TenantActivityStatusHOT})
+		}
+		return req
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		m := newMetaClass(false)
+		require.Nil(t, m.AddTenants("A", addReq("Tenant1", "tenant1"), 1, 1, time.Time{}))
+		assert.Len(t, m.Sharding.Physical, 2)
+		res, _ := m.TenantsShards("C", "TENANT1")
+		assert.Empty(t, res)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		m := newMetaClass(true)
+		assert.NotNil(t, m.AddTenants("A", addReq("Tenant1", "tenant1"), 1, 1, time.Time{}))
+		assert.Empty(t, m.Sharding.Physical)
+
+		req := addReq("Tenant1")
+		require.Nil(t, m.AddTenants("A", req, 1, 2, time.Time{}))
+		assert.Equal(t, "tenant1", req.Tenants[0].Name)
+		assert.Contains(t, m.Sharding.Physical, "tenant1")
+
+		res, _ := m.TenantsShards("C", "TENANT1")
+		assert.Equal(t, map[string]string{"TENANT1": models.TenantActivityStatusHOT}, res)
+		owner, _, err := m.ShardOwner("TeNaNt1")
+		require.Nil(t, err)
+		assert.Equal(t, "A", owner)
+
+		updateReq := &command.UpdateTenantsRequest{
+			Tenants:      []*command.Tenant{{Name: "TENANT1", Status: models.TenantActivityStatusCOLD}},
+			ClusterNodes: []string{"A"},
+		}
+		require.Nil(t, m.UpdateTenants("A", updateReq, 3, time.Time{}))
+		assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["tenant1"].Status)
+
+		require.Nil(t, m.DeleteTenants(&command.DeleteTenantsRequest{Tenants: []string{"Tenant1"}}, 4, time.Time{}))
+		assert.Empty(t, m.Sharding.Physical)
+	})
+}
+
+func TestMetaClassCaseInsensitiveCollisions(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"tenant1": {Name: "tenant1"},
+		"Tenant1": {Name: "Tenant1"},
+		"TENANT1": {Name: "TENANT1"},
+		"tenant2": {Name: "tenant2"},
+		"Tenant3": {Name: "Tenant3"},
+	}}}
+	assert.Equal(t, map[string][]string{
+		"tenant1": {"TENANT1", "Tenant1", "tenant1"},
+	}, m.CaseInsensitiveCollisions())
+}
+
+func TestMetaClassFreezeCandidates(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", BelongsToNodes: []string{"B"}, Status: models.TenantActivityStatusCOLD},
+		"T3": {Name: "T3", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusFROZEN},
+		"T4": {Name: "T4", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+	}}}
+
+	tenants, missing := m.FreezeCandidates([]string{"T2", "T1", "T3", "X", "T1"})
+	assert.Equal(t, []string{"T1", "T2"}, tenants)
+	assert.Equal(t, []string{"X"}, missing)
+	assert.Equal(t, models.TenantActivityStatusHOT, m.Sharding.Physical["T1"].Status)
+
+	req := &command.UpdateTenantsRequest{}
+	for _, name := range tenants {
+		req.Tenants = append(req.Tenants, &command.Tenant{Name: name, Status: models.TenantActivityStatusFROZEN})
+	}
+	require.Nil(t, m.UpdateTenants("A", req, 3, time.UnixMilli(7)))
+	// only the tenants of node A are left for its DB
+	assert.Equal(t, []*command.Tenant{{Name: "T1", Status: types.TenantActivityStatusFREEZING}}, req.Tenants)
+	for _, name := range []string{"T1", "T2"} {
+		p := m.Sharding.Physical[name]
+		assert.Equal(t, types.TenantActivityStatusFREEZING, p.Status)
+		assert.Equal(t, int64(7), p.LastModifiedUnix)
+		process := m.ShardProcesses[shardProcessID(name, command.TenantProcessRequest_ACTION_FREEZING)]
+		assert.Len(t, process, len(p.BelongsToNodes))
+	}
+
+	// tenants being frozen aren't candidates anymore
+	tenants, missing = m.FreezeCandidates([]string{"T1", "T2", "T3"})
+	assert.Empty(t, tenants)
+	assert.Empty(t, missing)
+}
+
+func TestMetaClassTenantPlacement(t *testing.T) {
+	assert.True(t, validTenantPlacement([]string{"A"}, 0))
+	assert.True(t, validTenantPlacement([]string{"A", "B"}, 2))
+	assert.False(t, validTenantPlacement([]string{"A"}, 2))
+	assert.False(t, validTenantPlacement([]string{"A", "B", "C"}, 2))
+	assert.False(t, validTenantPlacement([]string{"A", "A"}, 2))
+
+	m := &metaClass{
+		Class:    models.Class{ReplicationConfig: &models.ReplicationConfig{Factor: 2}},
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{}},
+	}
+	_, err := m.ApplyTenantBatch("A", TenantBatch{Creates: []TenantSpec{
+		{Name: "T1", Nodes: []string{"A", "B"}},
+		{Name: "T2", Nodes: []string{"A"}},
+	}}, 1, time.Time{})
+	assert.ErrorContains(t, err, "T2")
+	assert.Empty(t, m.Sharding.Physical, "nothing is applied")
+
+	_, err = m.ApplyTenantBatch("A", TenantBatch{
+		Creates:            []TenantSpec{{Name: "T1", Nodes: []string{"A", "B"}}, {Name: "T2", Nodes: []string{"A"}}},
+		SkipPlacementCheck: true,
+	}, 1, time.Time{})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"A"}, m.Sharding.Physical["T2"].BelongsToNodes)
+
+	req := &command.AddTenantsRequest{
+		ClusterNodes: []string{"A", "B"},
+		Tenants: []*command.Tenant{
+			{Name: "T3"},
+			{Name: "T4", Nodes: []string{"A"}},
+			{Name: "T5", Nodes: []string{"B", "A"}},
+		},
+	}
+	err = m.AddTenants("A", req, 2, 2, time.Time{})
+	var rejected TenantsError
+	require.ErrorAs(t, err, &rejected)
+	assert.Len(t, rejected, 1)
+	assert.Contains(t, rejected, "T4")
+	assert.Len(t, m.Sharding.Physical["T3"].BelongsToNodes, 2)
+	assert.NotContains(t, m.Sharding.Physical, "T4")
+	assert.Equal(t, []string{"B", "A"}, m.Sharding.Physical["T5"].BelongsToNodes)
+	require.Len(t, req.Tenants, 2, "only applied tenants reach the db")
+	assert.Equal(t, "T3", req.Tenants[0].Name)
+	assert.Equal(t, "T5", req.Tenants[1].Name)
+
+	req = &command.AddTenantsRequest{
+		Tenants:            []*command.Tenant{{Name: "T4", Nodes: []string{"A"}}},
+		SkipPlacementCheck: true,
+	}
+	require.Nil(t, m.AddTenants("A", req, 2, 3, time.Time{}))
+	assert.Equal(t, []string{"A"}, m.Sharding.Physical["T4"].BelongsToNodes)
+}
+
+func TestMetaClassUpdateTenantsOwner(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusHOT},
+	}}}
+	req := func(owner, status string) *command.UpdateTenantsRequest {
+		return &command.UpdateTenantsRequest{Tenants: []*command.Tenant{{Name: "T1", Status: status}}, Owner: owner}
+	}
+
+	assert.ErrorIs(t, m.UpdateTenants("A", req("C", models.TenantActivityStatusCOLD), 1, time.Time{}), ErrNotTenantOwner)
+	assert.ErrorIs(t, m.UpdateTenants("A", req("A", "WARM"), 2, time.Time{}), ErrInvalidStatusTransition)
+	assert.Equal(t, models.TenantActivityStatusHOT, m.Sharding.Physical["T1"].Status)
+
+	require.Nil(t, m.UpdateTenants("A", req("B", models.TenantActivityStatusCOLD), 3, time.Time{}))
+	assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T1"].Status)
+	changes, _ := m.TenantChangesSince(2)
+	assert.Equal(t, []TenantChange{{Tenant: "T1", Type: TenantUpdated, Status: models.TenantActivityStatusCOLD, Version: 3}}, changes)
+}
+
+func TestMetaClassSuspendTenantCreation(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+	}}}
+	addReq := func() *command.AddTenantsRequest {
+		return &command.AddTenantsRequest{Tenants: []*command.Tenant{{Name: "T3"}}, ClusterNodes: []string{"A"}}
+	}
+
+	m.SuspendTenantCreation(true)
+	assert.ErrorIs(t, m.AddTenants("A", addReq(), 1, 1, time.Time{}), ErrTenantCreationSuspended)
+	assert.NotContains(t, m.Sharding.Physical, "T3")
+
+	updateReq := &command.UpdateTenantsRequest{
+		Tenants:      []*command.Tenant{{Name: "T1", Status: models.TenantActivityStatusCOLD}},
+		ClusterNodes: []string{"A"},
+	}
+	require.Nil(t, m.UpdateTenants("A", updateReq, 2, time.Time{}))
+	assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T1"].Status)
+	require.Nil(t, m.DeleteTenants(&command.DeleteTenantsRequest{Tenants: []string{"T2"}}, 3, time.Time{}))
+	assert.NotContains(t, m.Sharding.Physical, "T2")
+
+	m.SuspendTenantCreation(false)
+	require.Nil(t, m.AddTenants("A", addReq(), 1, 4, time.Time{}))
+	assert.Contains(t, m.Sharding.Physical, "T3")
+}
+
+func TestMetaClassFreezeCandidatesOnNode(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusCOLD},
+		"T3": {Name: "T3", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusFROZEN},
+		"T4": {Name: "T4", BelongsToNodes: []string{"B", "A"}, Status: models.TenantActivityStatusHOT},
+		"T5": {Name: "T5", BelongsToNodes: []string{"A"}, Status: types.TenantActivityStatusFREEZING},
+	}}}
+
+	assert.Equal(t, []string{"T1", "T2"}, m.FreezeCandidatesOnNode("A"))
+	assert.Equal(t, []string{"T4"}, m.FreezeCandidatesOnNode("B"))
+	assert.Empty(t, m.FreezeCandidatesOnNode("C"))
+}
+
+func TestMetaClassFreezeCandidatesByLabel(t *testing.T) {
+	free := map[string]string{"tier": "free"}
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT, Metadata: free},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusFROZEN, Metadata: free},
+		"T3": {Name: "T3", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT, Metadata: map[string]string{"tier": "paid"}},
+		"T4": {Name: "T4", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+		"T5": {Name: "T5", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusCOLD, Metadata: free},
+	}}}
+
+	assert.Equal(t, []string{"T1", "T5"}, m.FreezeCandidatesByLabel("tier", "free"))
+	assert.Equal(t, []string{"T3"}, m.FreezeCandidatesByLabel("tier", "paid"))
+	assert.Empty(t, m.FreezeCandidatesByLabel("region", "eu"))
+}
+
+func TestMetaClassSetTenantNodes(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}, Status: models.TenantActivityStatusHOT},
+	}}}
+	before := m.Sharding.Physical["T1"].BelongsToNodes
+
+	assert.ErrorIs(t, m.SetTenantNodes("T2", []string{"A"}, 1, time.Time{}), ErrShardNotFound)
+	assert.NotNil(t, m.SetTenantNodes("T1", nil, 1, time.Time{}))
+	assert.NotNil(t, m.SetTenantNodes("T1", []string{"C", "C"}, 1, time.Time{}))
+	assert.Equal(t, []string{"A", "B"}, m.Sharding.Physical["T1"].BelongsToNodes)
+	require.Nil(t, m.SetTenantNodes("T1", []string{"A", "B"}, 1, time.Time{}))
+	assert.Equal(t, uint64(0), m.ShardVersion, "unchanged nodes aren't versioned")
+
+	nodes := []string{"C", "D", "E"}
+	require.Nil(t, m.SetTenantNodes("T1", nodes, 2, time.UnixMilli(7)))
+	nodes[0] = "X"
+	assert.Equal(t, []string{"C", "D", "E"}, m.Sharding.Physical["T1"].BelongsToNodes)
+	assert.Equal(t, models.TenantActivityStatusHOT, m.Sharding.Physical["T1"].Status)
+	assert.Equal(t, int64(7), m.Sharding.Physical["T1"].LastModifiedUnix)
+	assert.Equal(t, []string{"A", "B"}, before)
+	assert.Equal(t, uint64(2), m.ShardVersion)
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{{Tenant: "T1", Type: TenantUpdated, Status: models.TenantActivityStatusHOT, Version: 2}}, changes)
+}
+
+func TestMetaClassWouldAddTenantsChange(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A"}},
+	}}}
+
+	req := &command.AddTenantsRequest{Tenants: []*command.Tenant{{Name: "T1"}, nil, {Name: "T2"}}}
+	assert.False(t, m.WouldAddTenantsChange(req))
+	assert.Len(t, req.Tenants, 3)
+	assert.NotNil(t, req.Tenants[0])
+
+	req.Tenants = append(req.Tenants, &command.Tenant{Name: "T3"})
+	assert.True(t, m.WouldAddTenantsChange(req))
+	assert.False(t, m.WouldAddTenantsChange(&command.AddTenantsRequest{}))
+}
+
+func TestMetaClassStreamTenants(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T2": {Name: "T2"},
+		"T1": {Name: "T1"},
+		"T3": {Name: "T3"},
+	}}}
+
+	var names []string
+	for name := range m.StreamTenants(context.Background()) {
+		names = append(names, name)
+	}
+	assert.Equal(t, []string{"T1", "T2", "T3"}, names)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := m.StreamTenants(ctx)
+	assert.Equal(t, "T1", <-ch)
+	// the stream reflects the tenants at the time of the call
+	require.Nil(t, m.DeleteTenants(&command.DeleteTenantsRequest{Tenants: []string{"T3"}}, 1, time.Time{}))
+	cancel()
+	for range ch {
+	}
+	assert.Len(t, m.Sharding.Physical, 2)
+}
+
+func TestMetaClassTenantLastModified(t *testing.T) {
+	created := time.Unix(100, 0)
+	m := &metaClass{
+		CreationTime: created,
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T0": {Name: "T0", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+		}},
+	}
+	addReq := &command.AddTenantsRequest{
+		Tenants:      []*command.Tenant{{Name: "T1", Status: models.TenantActivityStatusHOT}},
+		ClusterNodes: []string{"A"},
+	}
+	require.Nil(t, m.AddTenants("A", addReq, 1, 1, time.Unix(200, 0)))
+
+	_, err := m.TenantLastModified("T2")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+	modified, err := m.TenantLastModified("T0")
+	require.Nil(t, err)
+	assert.Equal(t, created, modified)
+	modified, err = m.TenantLastModified("T1")
+	require.Nil(t, err)
+	assert.Equal(t, time.Unix(200, 0), modified)
+
+	updateReq := &command.UpdateTenantsRequest{
+		Tenants: []*command.Tenant{
+			{Name: "T0", Status: models.TenantActivityStatusHOT},
+			{Name: "T1", Status: models.TenantActivityStatusCOLD},
+		},
+		ClusterNodes: []string{"A"},
+	}
+	require.Nil(t, m.UpdateTenants("A", updateReq, 2, time.Unix(300, 0)))
+	modified, err = m.TenantLastModified("T1")
+	require.Nil(t, err)
+	assert.Equal(t, time.Unix(300, 0), modified)
+	// T0 didn't change
+	modified, err = m.TenantLastModified("T0")
+	require.Nil(t, err)
+	assert.Equal(t, created, modified)
+	assert.Equal(t, int64(300_000), m.Sharding.DeepCopy().Physical["T1"].LastModifiedUnix)
+}
+
+func TestMetaClassUpdateTenantsProcessLastModified(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1", BelongsToNodes: []string{"A", "B"}, Status: types.TenantActivityStatusFREEZING, LastModifiedUnix: 100_000},
+		}},
+		ShardProcesses: map[string]NodeShardProcess{
+			shardProcessID("T1", command.TenantProcessRequest_ACTION_FREEZING): {
+				"A": {Tenant: &command.Tenant{Name: "T1", Status: models.TenantActivityStatusFROZEN}, Op: command.TenantsProcess_OP_START},
+				"B": {Tenant: &command.Tenant{Name: "T1", Status: models.TenantActivityStatusFROZEN}, Op: command.TenantsProcess_OP_START},
+			},
+		},
+	}
+	done := func(node string) *command.TenantProcessRequest {
+		return &command.TenantProcessRequest{
+			Node:   node,
+			Action: command.TenantProcessRequest_ACTION_FREEZING,
+			TenantsProcesses: []*command.TenantsProcess{{
+				Tenant: &command.Tenant{Name: "T1", Status: models.TenantActivityStatusFROZEN},
+				Op:     command.TenantsProcess_OP_DONE,
+			}},
+		}
+	}
+
+	// the status doesn't change until all nodes are done
+	require.Nil(t, m.UpdateTenantsProcess("A", done("A"), 1, time.Unix(200, 0)))
+	assert.Equal(t, int64(100_000), m.Sharding.Physical["T1"].LastModifiedUnix)
+
+	require.Nil(t, m.UpdateTenantsProcess("A", done("B"), 2, time.Unix(300, 0)))
+	assert.Equal(t, models.TenantActivityStatusFROZEN, m.Sharding.Physical["T1"].Status)
+	modified, err := m.TenantLastModified("T1")
+	require.Nil(t, err)
+	assert.Equal(t, time.Unix(300, 0), modified)
+
+	// only the process which changed the status is in the change log
+	changes, _ := m.TenantChangesSince(1)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "T1", Type: TenantUpdated, Status: models.TenantActivityStatusFROZEN, Version: 2},
+	}, changes)
+}
+
+func TestMetaClassLastStatusChangeReason(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+	}}}
+	updateReq := func(status, reason string) *command.UpdateTenantsRequest {
+		return &command.UpdateTenantsRequest{
+			Tenants:      []*command.Tenant{{Name: "T1", Status: status}},
+			ClusterNodes: []string{"A"},
+			Reason:       reason,
+		}
+	}
+
+	reason, err := m.LastStatusChangeReason("T1")
+	require.Nil(t, err)
+	assert.Empty(t, reason)
+	_, err = m.LastStatusChangeReason("T3")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+
+	require.Nil(t, m.UpdateTenants("A", updateReq(models.TenantActivityStatusCOLD, "idle"), 1, time.UnixMilli(5)))
+	reason, err = m.LastStatusChangeReason("T1")
+	require.Nil(t, err)
+	assert.Equal(t, "idle", reason)
+	reason, err = m.LastStatusChangeReason("T2")
+	require.Nil(t, err)
+	assert.Empty(t, reason)
+
+	// no-op updates keep the reason
+	require.Nil(t, m.UpdateTenants("A", updateReq(models.TenantActivityStatusCOLD, "other"), 2, time.Time{}))
+	reason, err = m.LastStatusChangeReason("T1")
+	require.Nil(t, err)
+	assert.Equal(t, "idle", reason)
+
+	require.Nil(t, m.UpdateTenants("A", updateReq(models.TenantActivityStatusHOT, ""), 3, time.UnixMilli(6)))
+	reason, err = m.LastStatusChangeReason("T1")
+	require.Nil(t, err)
+	assert.Empty(t, reason)
+	assert.Equal(t, []sharding.StatusChange{
+		{Status: models.TenantActivityStatusCOLD, Reason: "idle", ModifiedUnix: 5},
+		{Status: models.TenantActivityStatusHOT, ModifiedUnix: 6},
+	}, m.Sharding.Physical["T1"].StatusHistory)
+
+	// the history is bounded
+	for i := 0; i < sharding.MaxStatusHistory; i++ {
+		status := models.TenantActivityStatusCOLD
+		if i%2 == 1 {
+			status = models.TenantActivityStatusHOT
+		}
+		require.Nil(t, m.UpdateTenants("A", updateReq(status, fmt.Sprint(i)), uint64(4+i), time.Time{}))
+	}
+	history := m.Sharding.Physical["T1"].StatusHistory
+	assert.Len(t, history, sharding.MaxStatusHistory)
+	assert.Equal(t, "0", history[0].Reason)
+	reason, err = m.LastStatusChangeReason("T1")
+	require.Nil(t, err)
+	assert.Equal(t, fmt.Sprint(sharding.MaxStatusHistory-1), reason)
+}
+
+func TestMetaClassTenantsModifiedAfter(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", LastModifiedUnix: 100_000},
+		"T2": {Name: "T2", LastModifiedUnix: 300_000},
+		"T3": {Name: "T3", LastModifiedUnix: 200_000},
+		"T4": {Name: "T4"},
+	}}}
+	assert.Equal(t, []string{"T1", "T2", "T3"}, m.TenantsModifiedAfter(time.Time{}))
+	assert.Equal(t, []string{"T2", "T3"}, m.TenantsModifiedAfter(time.Unix(100, 0)))
+	assert.Empty(t, m.TenantsModifiedAfter(time.Unix(300, 0)))
+}
+
+func TestMetaClassSnapshotStatuses(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusCOLD},
+		"T3": {Name: "T3", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+	}}}
+
+	snapshot := m.SnapshotStatuses()
+	assert.Equal(t, map[string]string{
+		"T1": models.TenantActivityStatusHOT,
+		"T2": models.TenantActivityStatusCOLD,
+		"T3": models.TenantActivityStatusHOT,
+	}, snapshot)
+
+	require.Nil(t, m.UpdateTenants("A", &command.UpdateTenantsRequest{Tenants: []*command.Tenant{
+		{Name: "T1", Status: models.TenantActivityStatusCOLD},
+		{Name: "T2", Status: models.TenantActivityStatusHOT},
+	}}, 1, time.Time{}))
+	require.Nil(t, m.DeleteTenants(&command.DeleteTenantsRequest{Tenants: []string{"T3"}}, 2, time.Time{}))
+
+	_, err := m.RestoreStatusesRequest(map[string]string{"T1": "WARM", "T2": models.TenantActivityStatusHOT})
+	assert.ErrorIs(t, err, ErrUnknownTenantStatus)
+
+	req, err := m.RestoreStatusesRequest(snapshot)
+	require.Nil(t, err)
+	assert.Equal(t, []*command.Tenant{
+		{Name: "T1", Status: models.TenantActivityStatusHOT},
+		{Name: "T2", Status: models.TenantActivityStatusCOLD},
+	}, req.Tenants)
+	assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T1"].Status, "building the request doesn't change tenants")
+
+	require.Nil(t, m.UpdateTenants("A", req, 3, time.Time{}))
+	assert.Equal(t, models.TenantActivityStatusHOT, m.Sharding.Physical["T1"].Status)
+	assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T2"].Status)
+	assert.NotContains(t, m.Sharding.Physical, "T3")
+
+	req, err = m.RestoreStatusesRequest(snapshot)
+	require.Nil(t, err)
+	assert.Empty(t, req.Tenants)
+}
+
+func TestMetaClassValidateDelete(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", Status: models.TenantActivityStatusHOT},
+		"T2": {Name: "T2", Status: models.TenantActivityStatusCOLD},
+		"T3": {Name: "T3"},
+	}}}
+	req := &command.DeleteTenantsRequest{Tenants: []string{"T3", "T2", "T1", "T1", "T4"}}
+
+	warnings, err := m.ValidateDelete(req)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"T1", "T3"}, warnings)
+
+	assert.NotNil(t, m.SetMaxTenantDeletes(-1))
+	require.Nil(t, m.SetMaxTenantDeletes(2))
+	warnings, err = m.ValidateDelete(req)
+	assert.ErrorIs(t, err, ErrTooManyTenantDeletes)
+	assert.Equal(t, []string{"T1", "T3"}, warnings)
+	assert.ErrorIs(t, m.DeleteTenants(req, 1, time.Time{}), ErrTooManyTenantDeletes)
+	assert.Len(t, m.Sharding.Physical, 3)
+
+	_, err = m.ValidateDelete(&command.DeleteTenantsRequest{Tenants: []string{"T1", "T2", "T4"}})
+	require.Nil(t, err)
+}
+
+func TestMetaClassMergeTenantMetadata(t *testing.T) {
+	labels := map[string]string{"tier": "free", "region": "eu"}
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Metadata: labels},
+		"T2": {Name: "T2", BelongsToNodes: []string{"A"}},
+	}}}
+
+	err := m.MergeTenantMetadata(map[string]map[string]string{
+		"T1": {"tier": "paid", "owner": "x"},
+		"T2": {"tier": "free"},
+		"T4": {"tier": "free"},
+		"T3": {},
+	}, 1, time.UnixMilli(3))
+	var rejected TenantsError
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, TenantsError{"T3": ErrShardNotFound, "T4": ErrShardNotFound}, rejected)
+	assert.Equal(t, map[string]string{"tier": "paid", "region": "eu", "owner": "x"}, m.Sharding.Physical["T1"].Metadata)
+	assert.Equal(t, map[string]string{"tier": "free"}, m.Sharding.Physical["T2"].Metadata)
+	assert.Equal(t, map[string]string{"tier": "free", "region": "eu"}, labels)
+	assert.Equal(t, int64(3), m.Sharding.Physical["T1"].LastModifiedUnix)
+
+	// labels which are set already aren't a change
+	require.Nil(t, m.MergeTenantMetadata(map[string]map[string]string{"T1": {"tier": "paid"}}, 2, time.UnixMilli(5)))
+	assert.Equal(t, int64(3), m.Sharding.Physical["T1"].LastModifiedUnix)
+	changes, _ := m.TenantChangesSince(0)
+	assert.Equal(t, []TenantChange{
+		{Tenant: "T1", Type: TenantUpdated, Version: 1},
+		{Tenant: "T2", Type: TenantUpdated, Version: 1},
+	}, changes)
+}
+
+func TestMetaClassAddTenantsStrict(t *testing.T) {
+	m := &metaClass{Sharding: sharding.State{Physical: map[string]sharding.Physical{
+		"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+	}}}
+	addReq := func(names ...string) *command.AddTenantsRequest {
+		req := &command.AddTenantsRequest{ClusterNodes: []string{"A"}}
+		for _, name := range names {
+			req.Tenants = append(req.Tenants, &command.Tenant{Name: name, Status: models.TenantActivityStatusHOT})
+		}
+		return req
+	}
+
+	err := m.AddTenantsStrict("A", addReq("T2", "T1", "T3"), 1, time.Unix(100, 0))
+	assert.ErrorIs(t, err, ErrTenantExists)
+	assert.ErrorContains(t, err, "T1")
+	assert.Len(t, m.Sharding.Physical, 1)
+
+	assert.Equal(t, uint64(0), m.ShardVersion)
+
+	require.Nil(t, m.AddTenantsStrict("A", addReq("T2", "T3"), 2, time.Unix(200, 0)))
+	assert.Equal(t, uint64(2), m.ShardVersion)
+	assert.Equal(t, int64(200_000), m.Sharding.Physical["T3"].LastModifiedUnix)
+	assert.Len(t, m.Sharding.Physical, 3)
+	assert.Equal(t, []string{"A"}, m.Sharding.Physical["T2"].BelongsToNodes)
+}
@@ -0,0 +1,101 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"fmt"
+	"time"
+
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+)
+
+// maxTenantTombstones bounds the number of tombstones kept per class
+const maxTenantTombstones = 1024
+
+// tenantTombstones records when tenants were deleted.
+// Tombstones are only recorded if TTL is positive. Both the TTL and the tombstones are
+// part of the class snapshot, and all times are the times the commands were appended to the log,
+// so every node accepts and rejects the same tenants.
+type tenantTombstones struct {
+	TTL     time.Duration        `json:"ttl,omitempty"`
+	Deleted map[string]time.Time `json:"deleted,omitempty"`
+}
+
+// add records that name was deleted at the specified time
+func (t *tenantTombstones) add(name string, at time.Time) {
+	if t.TTL <= 0 {
+		return
+	}
+	if t.Deleted == nil {
+		t.Deleted = make(map[string]time.Time)
+	}
+	if len(t.Deleted) >= maxTenantTombstones {
+		t.prune(at)
+	}
+	if len(t.Deleted) >= maxTenantTombstones {
+		var oldest string
+		var oldestAt time.Time
+		for n, deletedAt := range t.Deleted {
+			if oldest == "" || deletedAt.Before(oldestAt) || (deletedAt.Equal(oldestAt) && n < oldest) {
+				oldest, oldestAt = n, deletedAt
+			}
+		}
+		delete(t.Deleted, oldest)
+	}
+	t.Deleted[name] = at
+}
+
+// prune removes all tombstones which expired before now
+func (t *tenantTombstones) prune(now time.Time) {
+	for n, at := range t.Deleted {
+		if now.Sub(at) >= t.TTL {
+			delete(t.Deleted, n)
+		}
+	}
+}
+
+// contains returns true if name was deleted within the tombstone TTL
+func (t *tenantTombstones) contains(name string, now time.Time) bool {
+	at, ok := t.Deleted[name]
+	return ok && now.Sub(at) < t.TTL
+}
+
+// SetTenantTombstoneTTL sets how long deleted tenants can't be created again.
+// A non-positive ttl disables tombstones, which is the default.
+func (m *metaClass) SetTenantTombstoneTTL(ttl time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	m.sequence.Add(1)
+
+	m.Tombstones.TTL = ttl
+	if ttl <= 0 {
+		m.Tombstones.Deleted = nil
+	}
+}
+
+// checkTombstones returns ErrTenantTombstoned if any of the tenants to be created at
+// the specified time was deleted recently
+func (m *metaClass) checkTombstones(tenants []*command.Tenant, at time.Time) error {
+	if len(m.Tombstones.Deleted) == 0 {
+		return nil
+	}
+	var names []string
+	for _, t := range tenants {
+		if _, exists := m.Sharding.Physical[t.Name]; !exists && m.Tombstones.contains(t.Name, at) {
+			names = append(names, t.Name)
+		}
+	}
+	if len(names) > 0 {
+		return fmt.Errorf("%w: %v", ErrTenantTombstoned, names)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassTenantTombstones(t *testing.T) {
+	m := &metaClass{
+		Class:    models.Class{Class: "C"},
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{}, PartitioningEnabled: true},
+	}
+	addReq := func(names ...string) *command.AddTenantsRequest {
+		req := &command.AddTenantsRequest{ClusterNodes: []string{"A"}}
+		for _, name := range names {
+			req.Tenants = append(req.Tenants, &command.Tenant{Name: name, Status: models.TenantActivityStatusHOT})
+		}
+		return req
+	}
+	deleteReq := &command.DeleteTenantsRequest{Tenants: []string{"T1"}}
+
+	start := time.Unix(1000, 0)
+
+	// disabled by default
+	require.Nil(t, m.AddTenants("A", addReq("T1"), 1, 1, start))
+	require.Nil(t, m.DeleteTenants(deleteReq, 2, start))
+	require.Nil(t, m.AddTenants("A", addReq("T1"), 1, 3, start))
+
+	m.SetTenantTombstoneTTL(time.Hour)
+	require.Nil(t, m.DeleteTenants(deleteReq, 4, start))
+	err := m.AddTenants("A", addReq("T1", "T2"), 1, 5, start.Add(time.Minute))
+	assert.ErrorIs(t, err, ErrTenantTombstoned)
+	assert.Empty(t, m.Sharding.Physical, "nothing is applied on rejection")
+
+	// tombstones are part of the snapshot
+	b, err := json.Marshal(m)
+	require.Nil(t, err)
+	restored := &metaClass{}
+	require.Nil(t, json.Unmarshal(b, restored))
+	assert.Equal(t, time.Hour, restored.Tombstones.TTL)
+	assert.True(t, restored.Tombstones.contains("T1", start.Add(time.Minute)))
+
+	// tombstones expire relative to the time of the command
+	require.Nil(t, m.AddTenants("A", addReq("T1", "T2"), 1, 6, start.Add(time.Hour)))
+	assert.Len(t, m.Sharding.Physical, 2)
+
+	// the number of tombstones is bounded
+	for i := 0; i < maxTenantTombstones+10; i++ {
+		m.Tombstones.add(string(rune('a'+i)), start.Add(time.Duration(i)*time.Second))
+	}
+	assert.Len(t, m.Tombstones.Deleted, maxTenantTombstones)
+	assert.NotContains(t, m.Tombstones.Deleted, "a", "the oldest tombstones are removed")
+}
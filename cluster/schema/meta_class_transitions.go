@@ -0,0 +1,99 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"fmt"
+
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/cluster/types"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+	"golang.org/x/exp/slices"
+)
+
+// tenantStatusTransitions maps the activity status of a tenant to the statuses it can be updated to.
+// A frozen tenant has to be unfrozen to COLD before it can be activated again.
+// Tenants being unfrozen can't be updated until unfreezing is done.
+// Tenants can't be updated to a status which isn't part of the table.
+var tenantStatusTransitions = map[string][]string{
+	models.TenantActivityStatusHOT:       {models.TenantActivityStatusCOLD, models.TenantActivityStatusFROZEN},
+	models.TenantActivityStatusCOLD:      {models.TenantActivityStatusHOT, models.TenantActivityStatusFROZEN},
+	models.TenantActivityStatusFROZEN:    {models.TenantActivityStatusCOLD},
+	types.TenantActivityStatusFREEZING:   {models.TenantActivityStatusCOLD},
+	types.TenantActivityStatusUNFREEZING: {},
+}
+
+// checkStatusTransition checks whether tenant p can be updated to status.
+// It returns noop if p already is or is about to be in status,
+// and otherwise a non-empty reason if the transition isn't allowed.
+func (m *metaClass) checkStatusTransition(p sharding.Physical, status string) (noop bool, reason string) {
+	from := p.ActivityStatus()
+	switch from {
+	case status:
+		return true, ""
+	case types.TenantActivityStatusFREEZING:
+		// ignore multiple freezing
+		if status == models.TenantActivityStatusFROZEN {
+			return true, ""
+		}
+	case types.TenantActivityStatusUNFREEZING:
+		// ignore multiple unfreezing
+		if status == m.unfreezingStatus(p.Name) {
+			return true, ""
+		}
+	}
+	if _, ok := tenantStatusTransitions[status]; !ok {
+		return false, fmt.Sprintf("unknown status %q", status)
+	}
+	if !slices.Contains(tenantStatusTransitions[from], status) {
+		return false, fmt.Sprintf("transition from %s to %s is not allowed", from, status)
+	}
+	return false, ""
+}
+
+// unfreezingStatus returns the status the specified tenant is being unfrozen to
+func (m *metaClass) unfreezingStatus(name string) string {
+	processes := m.ShardProcesses[shardProcessID(name, command.TenantProcessRequest_ACTION_UNFREEZING)]
+	for _, process := range processes {
+		return process.Tenant.Status
+	}
+	return ""
+}
+
+// ValidateTransitions checks the status transitions requested by req without applying them.
+// It returns the tenants whose transition isn't allowed mapped to the reason.
+// Tenants which don't exist are reported in an error wrapping ErrShardNotFound.
+func (m *metaClass) ValidateTransitions(req *command.UpdateTenantsRequest) (invalid map[string]string, err error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	invalid = make(map[string]string)
+	var missing []string
+	for _, t := range req.Tenants {
+		if t == nil {
+			continue
+		}
+		p, ok := m.Sharding.Physical[m.tenantName(t.Name)]
+		if !ok {
+			missing = append(missing, t.Name)
+			continue
+		}
+		if _, reason := m.checkStatusTransition(p, t.Status); reason != "" {
+			invalid[t.Name] = reason
+		}
+	}
+	if len(missing) > 0 {
+		return invalid, fmt.Errorf("%w: %v", ErrShardNotFound, missing)
+	}
+	return invalid, nil
+}
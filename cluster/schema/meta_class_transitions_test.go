@@ -0,0 +1,119 @@
+//                           _       _
+// __      _____  __ ___   ___  __ _| |_ ___
+// \ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+//  \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+//   \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+//
+//  Copyright © 2016 - 2024 Weaviate B.V. All rights reserved.
+//
+//  CONTACT: hello@weaviate.io
+//
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
+	"github.com/weaviate/weaviate/cluster/types"
+	"github.com/weaviate/weaviate/entities/models"
+	"github.com/weaviate/weaviate/usecases/sharding"
+)
+
+func TestMetaClassValidateTransitions(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"hot":        {Name: "hot", Status: models.TenantActivityStatusHOT},
+			"cold":       {Name: "cold", Status: models.TenantActivityStatusCOLD},
+			"frozen":     {Name: "frozen", Status: models.TenantActivityStatusFROZEN},
+			"freezing":   {Name: "freezing", Status: types.TenantActivityStatusFREEZING},
+			"unfreezing": {Name: "unfreezing", Status: types.TenantActivityStatusUNFREEZING},
+		}},
+		ShardProcesses: map[string]NodeShardProcess{
+			shardProcessID("unfreezing", command.TenantProcessRequest_ACTION_UNFREEZING): {
+				"A": {Tenant: &command.Tenant{Name: "unfreezing", Status: models.TenantActivityStatusCOLD}},
+			},
+		},
+	}
+	before, _ := m.CopyShardingState()
+
+	tests := []struct {
+		tenant, status string
+		valid          bool
+	}{
+		{"hot", models.TenantActivityStatusHOT, true},
+		{"hot", models.TenantActivityStatusCOLD, true},
+		{"hot", models.TenantActivityStatusFROZEN, true},
+		{"cold", models.TenantActivityStatusHOT, true},
+		{"cold", models.TenantActivityStatusFROZEN, true},
+		{"frozen", models.TenantActivityStatusCOLD, true},
+		{"frozen", models.TenantActivityStatusHOT, false},
+		{"freezing", models.TenantActivityStatusFROZEN, true},
+		{"freezing", models.TenantActivityStatusCOLD, true},
+		{"freezing", models.TenantActivityStatusHOT, false},
+		{"unfreezing", models.TenantActivityStatusCOLD, true},
+		{"unfreezing", models.TenantActivityStatusHOT, false},
+		{"unfreezing", models.TenantActivityStatusFROZEN, false},
+		{"hot", "WARM", false},
+		{"cold", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.tenant+" to "+tc.status, func(t *testing.T) {
+			req := &command.UpdateTenantsRequest{Tenants: []*command.Tenant{{Name: tc.tenant, Status: tc.status}}}
+			invalid, err := m.ValidateTransitions(req)
+			require.Nil(t, err)
+			if tc.valid {
+				assert.Empty(t, invalid)
+			} else {
+				assert.Contains(t, invalid, tc.tenant)
+			}
+		})
+	}
+
+	req := &command.UpdateTenantsRequest{Tenants: []*command.Tenant{
+		{Name: "unfreezing", Status: models.TenantActivityStatusHOT},
+		{Name: "missing", Status: models.TenantActivityStatusHOT},
+	}}
+	invalid, err := m.ValidateTransitions(req)
+	assert.ErrorIs(t, err, ErrShardNotFound)
+	assert.Equal(t, map[string]string{"unfreezing": "transition from UNFREEZING to HOT is not allowed"}, invalid)
+
+	after, _ := m.CopyShardingState()
+	assert.Equal(t, before, after)
+}
+
+func TestMetaClassUpdateTenantsTransitions(t *testing.T) {
+	m := &metaClass{
+		Sharding: sharding.State{Physical: map[string]sharding.Physical{
+			"T1": {Name: "T1", BelongsToNodes: []string{"A"}, Status: models.TenantActivityStatusHOT},
+			"T2": {Name: "T2", BelongsToNodes: []string{"A"}, Status: types.TenantActivityStatusUNFREEZING},
+		}},
+	}
+	req := &command.UpdateTenantsRequest{
+		Tenants: []*command.Tenant{
+			{Name: "T1", Status: models.TenantActivityStatusCOLD},
+			{Name: "T2", Status: models.TenantActivityStatusFROZEN},
+			{Name: "T3", Status: models.TenantActivityStatusCOLD},
+		},
+		ClusterNodes: []string{"A"},
+	}
+	err := m.UpdateTenants("A", req, 1, time.Time{})
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+	assert.ErrorIs(t, err, ErrShardNotFound)
+
+	var rejected TenantsError
+	require.ErrorAs(t, err, &rejected)
+	assert.Len(t, rejected, 2)
+	assert.ErrorIs(t, rejected["T2"], ErrInvalidStatusTransition)
+	assert.ErrorIs(t, rejected["T3"], ErrShardNotFound)
+
+	// the valid transitions of the batch are applied
+	assert.Equal(t, models.TenantActivityStatusCOLD, m.Sharding.Physical["T1"].Status)
+	assert.Equal(t, types.TenantActivityStatusUNFREEZING, m.Sharding.Physical["T2"].Status)
+	assert.Equal(t, uint64(1), m.ShardVersion)
+	require.Len(t, req.Tenants, 1)
+	assert.Equal(t, "T1", req.Tenants[0].Name)
+}
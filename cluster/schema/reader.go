@@ -16,6 +16,7 @@ import (
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/prometheus/client_golang/prometheus"
+	command "github.com/weaviate/weaviate/cluster/proto/api"
 	"github.com/weaviate/weaviate/cluster/types"
 	"github.com/weaviate/weaviate/cluster/utils"
 	"github.com/weaviate/weaviate/entities/models"
@@ -156,6 +157,136 @@ func (rs SchemaReader) GetShardsStatus(class, tenant string) (models.ShardStatus
 	return rs.schema.GetShardsStatus(class, tenant)
 }
 
+// SnapshotStatuses returns the status of every tenant of class
+func (rs SchemaReader) SnapshotStatuses(class string) (map[string]string, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.SnapshotStatuses(), nil
+}
+
+// RestoreStatusesRequest returns the UpdateTenants request restoring the tenant statuses of snapshot
+func (rs SchemaReader) RestoreStatusesRequest(class string, snapshot map[string]string) (*command.UpdateTenantsRequest, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.RestoreStatusesRequest(snapshot)
+}
+
+// FreezeCandidates returns the tenants of class which can be frozen and the tenants which don't exist
+func (rs SchemaReader) FreezeCandidates(class string, names []string) (tenants, missing []string, err error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, nil, ErrClassNotFound
+	}
+	tenants, missing = meta.FreezeCandidates(names)
+	return tenants, missing, nil
+}
+
+// FreezeCandidatesOnNode returns the tenants of class whose primary owner is node which can be frozen
+func (rs SchemaReader) FreezeCandidatesOnNode(class, node string) ([]string, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.FreezeCandidatesOnNode(node), nil
+}
+
+// FreezeCandidatesByLabel returns the tenants of class whose metadata maps key to value which can be frozen
+func (rs SchemaReader) FreezeCandidatesByLabel(class, key, value string) ([]string, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.FreezeCandidatesByLabel(key, value), nil
+}
+
+// DuplicateShardOwners returns the shards of class which have a node more than once
+func (rs SchemaReader) DuplicateShardOwners(class string) ([]string, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.DuplicateShardOwners(), nil
+}
+
+// BlankPrimaries returns the shards of class with a blank primary which can and can't be repaired
+func (rs SchemaReader) BlankPrimaries(class string) (repairable, unrepairable []string, err error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, nil, ErrClassNotFound
+	}
+	repairable, unrepairable = meta.BlankPrimaries()
+	return repairable, unrepairable, nil
+}
+
+// EmptyStatusTenants returns the tenants of class with an empty status
+func (rs SchemaReader) EmptyStatusTenants(class string) ([]string, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.EmptyStatusTenants(), nil
+}
+
+// RemapNodesPlan returns the shards of class which RemapNodes would change
+func (rs SchemaReader) RemapNodesPlan(class string, mapping map[string]string) ([]string, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.RemapNodesPlan(mapping)
+}
+
+// ComputeRebalancePlan returns the moves needed to spread the primary ownership of the shards of class across nodes
+func (rs SchemaReader) ComputeRebalancePlan(class string, nodes []string) ([]ShardMove, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.ComputeRebalancePlan(nodes), nil
+}
+
+// HealReplicationBalanced returns the replicas to add to the shards of class with less replicas
+// than their replication factor and the updated loads of nodeLoads
+func (rs SchemaReader) HealReplicationBalanced(class string, nodeLoads map[string]int) (map[string][]string, map[string]int, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, nil, ErrClassNotFound
+	}
+	plan, newLoads := meta.HealReplicationBalanced(nodeLoads)
+	return plan, newLoads, nil
+}
+
+// RaiseFactorPlan returns the replicas needed to raise the replication factor of class to newFactor
+func (rs SchemaReader) RaiseFactorPlan(class string, newFactor int, candidateNodes []string, nodeLoads map[string]int) (map[string][]string, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.RaiseFactorPlan(newFactor, candidateNodes, nodeLoads)
+}
+
+// EnsureTenantBatch returns the batch which creates or updates tenant of class to match status and nodes
+func (rs SchemaReader) EnsureTenantBatch(class, tenant, status string, nodes []string) (TenantBatch, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return TenantBatch{}, ErrClassNotFound
+	}
+	return meta.EnsureTenantBatch(tenant, status, nodes), nil
+}
+
+// DuplicateProperties returns the properties of class which DedupeProperties would remove
+func (rs SchemaReader) DuplicateProperties(class string) ([]string, error) {
+	meta := rs.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.DuplicateProperties(), nil
+}
+
 func (rs SchemaReader) Len() int { return rs.schema.len() }
 
 func (rs SchemaReader) retry(f func(*schema) error) error {
@@ -14,8 +14,10 @@ package schema
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	command "github.com/weaviate/weaviate/cluster/proto/api"
 	"github.com/weaviate/weaviate/cluster/types"
@@ -29,6 +31,32 @@ var (
 	ErrClassExists   = errors.New("class already exists")
 	ErrClassNotFound = errors.New("class not found")
 	ErrShardNotFound = errors.New("shard not found")
+	// ErrShardExists is returned when adding a shard which exists already
+	ErrShardExists = errors.New("shard already exists")
+	// ErrLastShard is returned when removing the last shard of a non-partitioned class
+	ErrLastShard = errors.New("cannot remove the last shard")
+	// ErrShardOwnerNotFound is returned when a shard isn't assigned to any node
+	ErrShardOwnerNotFound = errors.New("owner node not found")
+	// ErrNotTenantOwner is returned when a node modifies a tenant it doesn't own
+	ErrNotTenantOwner = errors.New("node doesn't own tenant")
+	// ErrInvalidStatusTransition is returned when a tenant status update isn't allowed by the transition table
+	ErrInvalidStatusTransition = errors.New("invalid tenant status transition")
+	// ErrUnknownTenantStatus is returned when a tenant has a status which isn't a known tenant status
+	ErrUnknownTenantStatus = errors.New("unknown tenant status")
+	// ErrTenantCreationSuspended is returned when creating tenants while tenant creation is suspended
+	ErrTenantCreationSuspended = errors.New("tenant creation is suspended")
+	// ErrTenantTombstoned is returned when creating a tenant which was deleted recently
+	ErrTenantTombstoned = errors.New("tenant was deleted recently")
+	// ErrTooManyProperties is returned when adding properties would exceed the maximum property count
+	ErrTooManyProperties = errors.New("maximum number of properties exceeded")
+	// ErrTooManyTenantDeletes is returned when a request deletes more tenants than allowed at once
+	ErrTooManyTenantDeletes = errors.New("maximum number of tenant deletions exceeded")
+	// ErrTenantExists is returned when strictly creating tenants which exist already
+	ErrTenantExists = errors.New("tenant already exists")
+	// ErrRebalanceConflict is returned when a rebalance plan doesn't match the current state anymore
+	ErrRebalanceConflict = errors.New("rebalance plan conflicts with current state")
+	// ErrMultiTenancyNotSupported is returned by operations which can't be applied to multi-tenant classes
+	ErrMultiTenancyNotSupported = errors.New("not supported for multi-tenant classes")
 )
 
 type ClassInfo struct {
@@ -45,6 +73,32 @@ func (ci *ClassInfo) Version() uint64 {
 	return max(ci.ClassVersion, ci.ShardVersion)
 }
 
+// TenantsError is returned by tenant updates which were applied partially.
+// It maps each tenant which couldn't be updated to the reason, the other tenants of the request are applied.
+type TenantsError map[string]error
+
+func (e TenantsError) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = fmt.Sprintf("%s: %v", name, e[name])
+	}
+	return "tenants not updated: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap allows errors.Is to match the reasons of the rejected tenants
+func (e TenantsError) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
 type schema struct {
 	nodeID      string
 	shardReader shardReader
@@ -238,7 +292,7 @@ func (s *schema) multiTenancyEnabled(class string) (bool, *metaClass, ClassInfo,
 	return true, meta, info, nil
 }
 
-func (s *schema) addClass(cls *models.Class, ss *sharding.State, v uint64) error {
+func (s *schema) addClass(cls *models.Class, ss *sharding.State, v uint64, createdAt time.Time) error {
 	s.Lock()
 	defer s.Unlock()
 	_, exists := s.Classes[cls.Class]
@@ -246,7 +300,13 @@ func (s *schema) addClass(cls *models.Class, ss *sharding.State, v uint64) error
 		return ErrClassExists
 	}
 
-	s.Classes[cls.Class] = &metaClass{Class: *cls, Sharding: *ss, ClassVersion: v, ShardVersion: v}
+	s.Classes[cls.Class] = &metaClass{
+		Class:        *cls,
+		Sharding:     *ss,
+		ClassVersion: v,
+		ShardVersion: v,
+		CreationTime: createdAt,
+	}
 	return nil
 }
 
@@ -279,37 +339,195 @@ func (s *schema) addProperty(class string, v uint64, props ...*models.Property)
 	return meta.AddProperty(v, props...)
 }
 
-func (s *schema) addTenants(class string, v uint64, req *command.AddTenantsRequest) error {
+func (s *schema) addTenants(class string, v uint64, req *command.AddTenantsRequest, modifiedAt time.Time) error {
 	req.Tenants = removeNilTenants(req.Tenants)
 
 	if ok, meta, info, err := s.multiTenancyEnabled(class); !ok {
 		return err
 	} else {
-		return meta.AddTenants(s.nodeID, req, int64(info.ReplicationFactor), v)
+		return meta.AddTenants(s.nodeID, req, int64(info.ReplicationFactor), v, modifiedAt)
+	}
+}
+
+func (s *schema) deleteTenants(class string, v uint64, req *command.DeleteTenantsRequest, modifiedAt time.Time) error {
+	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
+		return err
+	} else {
+		return meta.DeleteTenants(req, v, modifiedAt)
+	}
+}
+
+func (s *schema) updateTenantReplicationFactor(class string, v uint64, req *command.UpdateTenantReplicationFactorRequest) error {
+	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
+		return err
+	} else {
+		return meta.SetTenantReplicationFactor(req.Tenant, int(req.Factor), req.ClusterNodes, v)
+	}
+}
+
+func (s *schema) suspendTenantCreation(class string, suspend bool) error {
+	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
+		return err
+	} else {
+		meta.SuspendTenantCreation(suspend)
+		return nil
+	}
+}
+
+func (s *schema) updateTenantTombstoneTTL(class string, ttl time.Duration) error {
+	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
+		return err
+	} else {
+		meta.SetTenantTombstoneTTL(ttl)
+		return nil
+	}
+}
+
+func (s *schema) updateTenants(class string, v uint64, req *command.UpdateTenantsRequest, modifiedAt time.Time) error {
+	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
+		return err
+	} else {
+		return meta.UpdateTenants(s.nodeID, req, v, modifiedAt)
+	}
+}
+
+func (s *schema) setStatusForNodeTenants(class string, v uint64, req *command.SetNodeTenantsStatusRequest, modifiedAt time.Time) (*command.UpdateTenantsRequest, error) {
+	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
+		return nil, err
+	} else {
+		return meta.SetStatusForNodeTenants(s.nodeID, req.Node, req.Status, v, modifiedAt)
 	}
 }
 
-func (s *schema) deleteTenants(class string, v uint64, req *command.DeleteTenantsRequest) error {
+func (s *schema) updateTenantsProcess(class string, v uint64, req *command.TenantProcessRequest, modifiedAt time.Time) error {
 	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
 		return err
 	} else {
-		return meta.DeleteTenants(req, v)
+		return meta.UpdateTenantsProcess(s.nodeID, req, v, modifiedAt)
 	}
 }
 
-func (s *schema) updateTenants(class string, v uint64, req *command.UpdateTenantsRequest) error {
+func (s *schema) setTenantNodes(class string, v uint64, req *command.SetTenantNodesRequest, modifiedAt time.Time) error {
 	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
 		return err
 	} else {
-		return meta.UpdateTenants(s.nodeID, req, v)
+		return meta.SetTenantNodes(req.Tenant, req.Nodes, v, modifiedAt)
+	}
+}
+
+func (s *schema) mergeTenantMetadata(class string, v uint64, req *command.MergeTenantMetadataRequest, modifiedAt time.Time) error {
+	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
+		return err
+	} else {
+		return meta.MergeTenantMetadata(req.Updates, v, modifiedAt)
+	}
+}
+
+func (s *schema) dedupeShardOwners(class string, v uint64, req *command.DedupeShardOwnersRequest, modifiedAt time.Time) error {
+	meta := s.metaClass(class)
+	if meta == nil {
+		return ErrClassNotFound
+	}
+	return meta.DedupeShardOwners(req.Shards, v, modifiedAt)
+}
+
+func (s *schema) repairBlankPrimaries(class string, v uint64, req *command.RepairBlankPrimariesRequest, modifiedAt time.Time) error {
+	meta := s.metaClass(class)
+	if meta == nil {
+		return ErrClassNotFound
+	}
+	return meta.RepairBlankPrimaries(req.Shards, v, modifiedAt)
+}
+
+func (s *schema) normalizeEmptyStatuses(class string, v uint64, req *command.NormalizeEmptyStatusesRequest, modifiedAt time.Time) (*command.UpdateTenantsRequest, error) {
+	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
+		return nil, err
+	} else {
+		return meta.NormalizeEmptyStatuses(s.nodeID, req.Tenants, req.DefaultStatus, v, modifiedAt)
+	}
+}
+
+func (s *schema) remapNodes(class string, v uint64, req *command.RemapNodesRequest, modifiedAt time.Time) error {
+	meta := s.metaClass(class)
+	if meta == nil {
+		return ErrClassNotFound
+	}
+	return meta.RemapNodes(req.Mapping, v, modifiedAt)
+}
+
+func (s *schema) resetTenant(class string, v uint64, req *command.ResetTenantRequest, modifiedAt time.Time) error {
+	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
+		return err
+	} else {
+		return meta.ResetTenant(req.Tenant, req.Status, req.Nodes, v, modifiedAt)
+	}
+}
+
+func (s *schema) applyRebalancePlan(class string, v uint64, req *command.ApplyRebalancePlanRequest, modifiedAt time.Time) error {
+	meta := s.metaClass(class)
+	if meta == nil {
+		return ErrClassNotFound
+	}
+	plan := make([]ShardMove, len(req.Moves))
+	for i, mv := range req.Moves {
+		plan[i] = ShardMove{Shard: mv.Shard, From: mv.From, To: mv.To}
+	}
+	return meta.ApplyRebalancePlan(plan, v, modifiedAt)
+}
+
+func (s *schema) addShard(class string, v uint64, req *command.AddShardRequest, modifiedAt time.Time) (*command.AddTenantsRequest, error) {
+	meta := s.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.AddShard(s.nodeID, req.Shard, req.Nodes, req.Status, v, modifiedAt)
+}
+
+func (s *schema) removeShard(class string, v uint64, req *command.RemoveShardRequest, modifiedAt time.Time) error {
+	meta := s.metaClass(class)
+	if meta == nil {
+		return ErrClassNotFound
+	}
+	return meta.RemoveShard(req.Shard, v, modifiedAt)
+}
+
+func (s *schema) addReplicas(class string, v uint64, req *command.AddReplicasRequest, modifiedAt time.Time) (*command.AddTenantsRequest, error) {
+	meta := s.metaClass(class)
+	if meta == nil {
+		return nil, ErrClassNotFound
+	}
+	return meta.AddReplicas(s.nodeID, req.Replicas, v, modifiedAt)
+}
+
+func (s *schema) applyTenantBatch(class string, v uint64, ops TenantBatch, modifiedAt time.Time) (TenantBatchResult, error) {
+	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
+		return TenantBatchResult{}, err
+	} else {
+		return meta.ApplyTenantBatch(s.nodeID, ops, v, modifiedAt)
+	}
+}
+
+func (s *schema) dedupeProperties(class string, v uint64) error {
+	meta := s.metaClass(class)
+	if meta == nil {
+		return ErrClassNotFound
+	}
+	return meta.DedupeProperties(v)
+}
+
+func (s *schema) setMaxPropertyCount(class string, v uint64, limit int) error {
+	meta := s.metaClass(class)
+	if meta == nil {
+		return ErrClassNotFound
 	}
+	return meta.SetMaxPropertyCount(limit, v)
 }
 
-func (s *schema) updateTenantsProcess(class string, v uint64, req *command.TenantProcessRequest) error {
+func (s *schema) setMaxTenantDeletes(class string, limit int) error {
 	if ok, meta, _, err := s.multiTenancyEnabled(class); !ok {
 		return err
 	} else {
-		return meta.UpdateTenantsProcess(s.nodeID, req, v)
+		return meta.SetMaxTenantDeletes(limit)
 	}
 }
 
@@ -321,7 +539,7 @@ func (s *schema) getTenants(class string, tenants []string) ([]*models.Tenant, e
 
 	// Read tenants using the meta lock guard
 	var res []*models.Tenant
-	f := func(_ *models.Class, ss *sharding.State) error {
+	f := func(cls *models.Class, ss *sharding.State) error {
 		if len(tenants) == 0 {
 			res = make([]*models.Tenant, len(ss.Physical))
 			i := 0
@@ -332,7 +550,7 @@ func (s *schema) getTenants(class string, tenants []string) ([]*models.Tenant, e
 		} else {
 			res = make([]*models.Tenant, 0, len(tenants))
 			for _, tenant := range tenants {
-				if status, ok := ss.Physical[tenant]; ok {
+				if status, ok := ss.Physical[entSchema.NormalizeTenantName(cls, tenant)]; ok {
 					res = append(res, makeTenant(tenant, entSchema.ActivityStatus(status.Status)))
 				}
 			}
@@ -137,12 +137,12 @@ func (st *Store) Apply(l *raft.Log) interface{} {
 
 	case api.ApplyRequest_TYPE_ADD_CLASS:
 		f = func() {
-			ret.Error = st.schemaManager.AddClass(&cmd, st.cfg.NodeID, schemaOnly)
+			ret.Error = st.schemaManager.AddClass(&cmd, st.cfg.NodeID, schemaOnly, l.AppendedAt)
 		}
 
 	case api.ApplyRequest_TYPE_RESTORE_CLASS:
 		f = func() {
-			ret.Error = st.schemaManager.RestoreClass(&cmd, st.cfg.NodeID, schemaOnly)
+			ret.Error = st.schemaManager.RestoreClass(&cmd, st.cfg.NodeID, schemaOnly, l.AppendedAt)
 		}
 
 	case api.ApplyRequest_TYPE_UPDATE_CLASS:
@@ -167,22 +167,117 @@ func (st *Store) Apply(l *raft.Log) interface{} {
 
 	case api.ApplyRequest_TYPE_ADD_TENANT:
 		f = func() {
-			ret.Error = st.schemaManager.AddTenants(&cmd, schemaOnly)
+			ret.Error = st.schemaManager.AddTenants(&cmd, schemaOnly, l.AppendedAt)
 		}
 
 	case api.ApplyRequest_TYPE_UPDATE_TENANT:
 		f = func() {
-			ret.Error = st.schemaManager.UpdateTenants(&cmd, schemaOnly)
+			ret.Error = st.schemaManager.UpdateTenants(&cmd, schemaOnly, l.AppendedAt)
 		}
 
 	case api.ApplyRequest_TYPE_DELETE_TENANT:
 		f = func() {
-			ret.Error = st.schemaManager.DeleteTenants(&cmd, schemaOnly)
+			ret.Error = st.schemaManager.DeleteTenants(&cmd, schemaOnly, l.AppendedAt)
 		}
 
 	case api.ApplyRequest_TYPE_TENANT_PROCESS:
 		f = func() {
-			ret.Error = st.schemaManager.UpdateTenantsProcess(&cmd, schemaOnly)
+			ret.Error = st.schemaManager.UpdateTenantsProcess(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_UPDATE_TENANT_TOMBSTONE_TTL:
+		f = func() {
+			ret.Error = st.schemaManager.UpdateTenantTombstoneTTL(&cmd, schemaOnly)
+		}
+
+	case api.ApplyRequest_TYPE_SUSPEND_TENANT_CREATION:
+		f = func() {
+			ret.Error = st.schemaManager.SuspendTenantCreation(&cmd, schemaOnly)
+		}
+
+	case api.ApplyRequest_TYPE_UPDATE_TENANT_REPLICATION_FACTOR:
+		f = func() {
+			ret.Error = st.schemaManager.UpdateTenantReplicationFactor(&cmd, schemaOnly)
+		}
+
+	case api.ApplyRequest_TYPE_SET_NODE_TENANTS_STATUS:
+		f = func() {
+			ret.Error = st.schemaManager.SetNodeTenantsStatus(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_SET_TENANT_NODES:
+		f = func() {
+			ret.Error = st.schemaManager.SetTenantNodes(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_MERGE_TENANT_METADATA:
+		f = func() {
+			ret.Error = st.schemaManager.MergeTenantMetadata(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_DEDUPE_SHARD_OWNERS:
+		f = func() {
+			ret.Error = st.schemaManager.DedupeShardOwners(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_REPAIR_BLANK_PRIMARIES:
+		f = func() {
+			ret.Error = st.schemaManager.RepairBlankPrimaries(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_NORMALIZE_EMPTY_STATUSES:
+		f = func() {
+			ret.Error = st.schemaManager.NormalizeEmptyStatuses(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_REMAP_NODES:
+		f = func() {
+			ret.Error = st.schemaManager.RemapNodes(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_RESET_TENANT:
+		f = func() {
+			ret.Error = st.schemaManager.ResetTenant(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_APPLY_REBALANCE_PLAN:
+		f = func() {
+			ret.Error = st.schemaManager.ApplyRebalancePlan(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_ADD_SHARD:
+		f = func() {
+			ret.Error = st.schemaManager.AddShard(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_REMOVE_SHARD:
+		f = func() {
+			ret.Error = st.schemaManager.RemoveShard(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_ADD_REPLICAS:
+		f = func() {
+			ret.Error = st.schemaManager.AddReplicas(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_APPLY_TENANT_BATCH:
+		f = func() {
+			ret.Error = st.schemaManager.ApplyTenantBatch(&cmd, schemaOnly, l.AppendedAt)
+		}
+
+	case api.ApplyRequest_TYPE_DEDUPE_PROPERTIES:
+		f = func() {
+			ret.Error = st.schemaManager.DedupeProperties(&cmd, schemaOnly)
+		}
+
+	case api.ApplyRequest_TYPE_SET_MAX_PROPERTY_COUNT:
+		f = func() {
+			ret.Error = st.schemaManager.SetMaxPropertyCount(&cmd, schemaOnly)
+		}
+
+	case api.ApplyRequest_TYPE_SET_MAX_TENANT_DELETES:
+		f = func() {
+			ret.Error = st.schemaManager.SetMaxTenantDeletes(&cmd, schemaOnly)
 		}
 
 	case api.ApplyRequest_TYPE_STORE_SCHEMA_V1:
@@ -389,6 +389,53 @@ func TestStoreApply(t *testing.T) {
 				m.store.Apply(&raft.Log{
 					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{Class: cls, State: ss}, nil),
 				})
+				m.indexer.On("UpdateTenants", mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		{
+			name: "UpdateTenant/PartialFailure",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_UPDATE_TENANT,
+				nil, &cmd.UpdateTenantsRequest{Tenants: []*command.Tenant{
+					{Name: "T1", Status: "WARM"},
+					{Name: "T2", Status: models.TenantActivityStatusCOLD},
+				}})},
+			resp: Response{Error: schema.ErrInvalidStatusTransition},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				ss := &sharding.State{Physical: map[string]sharding.Physical{"T1": {
+					Name:           "T1",
+					BelongsToNodes: []string{"Node-1"},
+					Status:         models.TenantActivityStatusHOT,
+				}, "T2": {
+					Name:           "T2",
+					BelongsToNodes: []string{"Node-1"},
+					Status:         models.TenantActivityStatusHOT,
+				}}}
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{Class: cls, State: ss}, nil),
+				})
+				m.indexer.On("UpdateTenants", mock.Anything, mock.Anything).Return(nil)
+			},
+			doAfter: func(ms *MockStore) error {
+				// T1 is rejected, the valid T2 transition is still applied to the schema and the db
+				for _, call := range ms.indexer.Calls {
+					if call.Method != "UpdateTenants" {
+						continue
+					}
+					got := call.Arguments.Get(1).(*cmd.UpdateTenantsRequest).Tenants
+					if len(got) != 1 || got[0].Name != "T2" {
+						return fmt.Errorf("db tenants want: [T2] got: %v", got)
+					}
+				}
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if got := shardingState.Physical["T1"].Status; got != models.TenantActivityStatusHOT {
+					return fmt.Errorf("T1 status want: %s got: %s", models.TenantActivityStatusHOT, got)
+				}
+				if got := shardingState.Physical["T2"].Status; got != models.TenantActivityStatusCOLD {
+					return fmt.Errorf("T2 status want: %s got: %s", models.TenantActivityStatusCOLD, got)
+				}
+				return nil
 			},
 		},
 		{
@@ -422,10 +469,12 @@ func TestStoreApply(t *testing.T) {
 				m.indexer.On("UpdateTenants", mock.Anything, mock.Anything).Return(nil)
 			},
 			doAfter: func(ms *MockStore) error {
+				changed := []sharding.StatusChange{{Status: models.TenantActivityStatusCOLD}}
 				want := map[string]sharding.Physical{"T1": {
 					Name:           "T1",
 					BelongsToNodes: []string{"THIS"},
 					Status:         models.TenantActivityStatusCOLD,
+					StatusHistory:  changed,
 				}, "T2": {
 					Name:           "T2",
 					BelongsToNodes: []string{"THIS"},
@@ -434,6 +483,7 @@ func TestStoreApply(t *testing.T) {
 					Name:           "T3",
 					BelongsToNodes: []string{"NODE-2"},
 					Status:         models.TenantActivityStatusCOLD,
+					StatusHistory:  changed,
 				}}
 
 				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
@@ -481,6 +531,390 @@ func TestStoreApply(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "UpdateTenantReplicationFactor/TenantNotFound",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_UPDATE_TENANT_REPLICATION_FACTOR,
+				cmd.UpdateTenantReplicationFactorRequest{Tenant: "T3", Factor: 2, ClusterNodes: []string{"Node-1", "Node-2"}}, nil)},
+			resp: Response{Error: schema.ErrShardNotFound},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{Class: cls, State: ss}, nil),
+				})
+			},
+		},
+		{
+			name: "UpdateTenantReplicationFactor/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_UPDATE_TENANT_REPLICATION_FACTOR,
+				cmd.UpdateTenantReplicationFactorRequest{Tenant: "T1", Factor: 2, ClusterNodes: []string{"Node-1", "Node-2"}}, nil)},
+			resp: Response{Error: nil},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{
+						Class: cls, State: &sharding.State{Physical: map[string]sharding.Physical{"T1": {Name: "T1"}}},
+					}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if got := shardingState.Physical["T1"].ReplicationFactor; got != 2 {
+					return fmt.Errorf("replication factor want: 2 got: %d", got)
+				}
+				return nil
+			},
+		},
+		{
+			name: "SuspendTenantCreation/ClassNotFound",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_SUSPEND_TENANT_CREATION,
+				cmd.SuspendTenantCreationRequest{Suspend: true}, nil)},
+			resp:     Response{Error: schema.ErrSchema},
+			doBefore: doFirst,
+		},
+		{
+			name: "SuspendTenantCreation/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_TENANT,
+				nil, &command.AddTenantsRequest{ClusterNodes: []string{"THIS"}, Tenants: []*command.Tenant{{Name: "T2"}}})},
+			resp: Response{Error: schema.ErrTenantCreationSuspended},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{
+						Class: cls, State: &sharding.State{Physical: map[string]sharding.Physical{}},
+					}, nil),
+				})
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_SUSPEND_TENANT_CREATION,
+						cmd.SuspendTenantCreationRequest{Suspend: true}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if len(shardingState.Physical) != 0 {
+					return fmt.Errorf("tenant must not be created while creation is suspended")
+				}
+				return nil
+			},
+		},
+		{
+			name:     "UpdateTenantTombstoneTTL/Unmarshal",
+			req:      raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_UPDATE_TENANT_TOMBSTONE_TTL, nil, &cmd.DeleteTenantsRequest{Tenants: []string{"T1"}})},
+			resp:     Response{Error: schema.ErrBadRequest},
+			doBefore: doFirst,
+		},
+		{
+			name: "UpdateTenantTombstoneTTL/ClassNotFound",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_UPDATE_TENANT_TOMBSTONE_TTL,
+				cmd.UpdateTenantTombstoneTTLRequest{TTL: time.Hour}, nil)},
+			resp:     Response{Error: schema.ErrSchema},
+			doBefore: doFirst,
+		},
+		{
+			name: "UpdateTenantTombstoneTTL/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_TENANT,
+				nil, &command.AddTenantsRequest{ClusterNodes: []string{"THIS"}, Tenants: []*command.Tenant{{Name: "T1"}}})},
+			resp: Response{Error: schema.ErrTenantTombstoned},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.indexer.On("DeleteTenants", mock.Anything, mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{
+						Class: cls, State: &sharding.State{
+							Physical: map[string]sharding.Physical{"T1": {}},
+						},
+					}, nil),
+				})
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_UPDATE_TENANT_TOMBSTONE_TTL,
+						cmd.UpdateTenantTombstoneTTLRequest{TTL: time.Hour}, nil),
+				})
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_DELETE_TENANT, nil, &cmd.DeleteTenantsRequest{Tenants: []string{"T1"}}),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if len(shardingState.Physical) != 0 {
+					return fmt.Errorf("deleted tenant must not be created again")
+				}
+				return nil
+			},
+		},
+		{
+			name: "SetNodeTenantsStatus/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_SET_NODE_TENANTS_STATUS,
+				cmd.SetNodeTenantsStatusRequest{Node: "Node-1", Status: models.TenantActivityStatusCOLD}, nil)},
+			resp: Response{Error: nil},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.indexer.On("UpdateTenants", mock.Anything, mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{
+						Class: cls, State: &sharding.State{Physical: map[string]sharding.Physical{
+							"T1": {Name: "T1", BelongsToNodes: []string{"Node-1"}, Status: models.TenantActivityStatusHOT},
+							"T2": {Name: "T2", BelongsToNodes: []string{"Node-2", "Node-1"}, Status: models.TenantActivityStatusHOT},
+						}},
+					}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if got := shardingState.Physical["T1"].Status; got != models.TenantActivityStatusCOLD {
+					return fmt.Errorf("T1 status want: %s got: %s", models.TenantActivityStatusCOLD, got)
+				}
+				if got := shardingState.Physical["T2"].Status; got != models.TenantActivityStatusHOT {
+					return fmt.Errorf("T2 status want: %s got: %s", models.TenantActivityStatusHOT, got)
+				}
+				return nil
+			},
+		},
+		{
+			name: "SetTenantNodes/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_SET_TENANT_NODES,
+				cmd.SetTenantNodesRequest{Tenant: "T1", Nodes: []string{"Node-2"}}, nil)},
+			resp: Response{Error: nil},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{Class: cls, State: ss}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if got := shardingState.Physical["T1"].BelongsToNodes; !reflect.DeepEqual(got, []string{"Node-2"}) {
+					return fmt.Errorf("T1 nodes want: [Node-2] got: %v", got)
+				}
+				return nil
+			},
+		},
+		{
+			name: "MergeTenantMetadata/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_MERGE_TENANT_METADATA,
+				cmd.MergeTenantMetadataRequest{Updates: map[string]map[string]string{"T1": {"tier": "free"}}}, nil)},
+			resp: Response{Error: nil},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{Class: cls, State: ss}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if got := shardingState.Physical["T1"].Metadata["tier"]; got != "free" {
+					return fmt.Errorf("T1 tier want: free got: %q", got)
+				}
+				return nil
+			},
+		},
+		{
+			name: "DedupeShardOwners/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_DEDUPE_SHARD_OWNERS,
+				cmd.DedupeShardOwnersRequest{Shards: []string{"T1"}}, nil)},
+			resp: Response{Error: nil},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{
+						Class: cls, State: &sharding.State{Physical: map[string]sharding.Physical{
+							"T1": {Name: "T1", BelongsToNodes: []string{"Node-1", "Node-1"}},
+						}},
+					}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if got := shardingState.Physical["T1"].BelongsToNodes; !reflect.DeepEqual(got, []string{"Node-1"}) {
+					return fmt.Errorf("T1 nodes want: [Node-1] got: %v", got)
+				}
+				return nil
+			},
+		},
+		{
+			name: "RepairBlankPrimaries/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_REPAIR_BLANK_PRIMARIES,
+				cmd.RepairBlankPrimariesRequest{Shards: []string{"T1"}}, nil)},
+			resp: Response{Error: nil},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{
+						Class: cls, State: &sharding.State{Physical: map[string]sharding.Physical{
+							"T1": {Name: "T1", BelongsToNodes: []string{"", "Node-1"}},
+						}},
+					}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if got := shardingState.Physical["T1"].BelongsToNodes; !reflect.DeepEqual(got, []string{"Node-1"}) {
+					return fmt.Errorf("T1 nodes want: [Node-1] got: %v", got)
+				}
+				return nil
+			},
+		},
+		{
+			name: "NormalizeEmptyStatuses/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_NORMALIZE_EMPTY_STATUSES,
+				cmd.NormalizeEmptyStatusesRequest{Tenants: []string{"T1"}, DefaultStatus: models.TenantActivityStatusCOLD}, nil)},
+			resp: Response{Error: nil},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.indexer.On("UpdateTenants", mock.Anything, mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{
+						Class: cls, State: &sharding.State{Physical: map[string]sharding.Physical{
+							"T1": {Name: "T1", BelongsToNodes: []string{"Node-1"}},
+						}},
+					}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if got := shardingState.Physical["T1"].Status; got != models.TenantActivityStatusCOLD {
+					return fmt.Errorf("T1 status want: %s got: %s", models.TenantActivityStatusCOLD, got)
+				}
+				return nil
+			},
+		},
+		{
+			name: "AddShard/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_SHARD,
+				cmd.AddShardRequest{Shard: "S2", Nodes: []string{"Node-1"}, Status: models.TenantActivityStatusHOT}, nil)},
+			resp: Response{Error: nil},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.indexer.On("AddTenants", mock.Anything, mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{Class: cls, State: ss}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				if _, ok := ms.store.SchemaReader().CopyShardingState("C1").Physical["S2"]; !ok {
+					return fmt.Errorf("shard S2 not found")
+				}
+				for _, call := range ms.indexer.Calls {
+					if call.Method != "AddTenants" {
+						continue
+					}
+					got := call.Arguments.Get(1).(*cmd.AddTenantsRequest).Tenants
+					if len(got) != 1 || got[0].Name != "S2" {
+						return fmt.Errorf("db shards want: [S2] got: %v", got)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "ApplyTenantBatch/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_APPLY_TENANT_BATCH,
+				cmd.ApplyTenantBatchRequest{
+					Creates:            []cmd.TenantSpec{{Name: "T3", Status: models.TenantActivityStatusHOT, Nodes: []string{"Node-1"}}},
+					Deletes:            []string{"T1"},
+					SkipPlacementCheck: true,
+				}, nil)},
+			resp: Response{Error: nil},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.indexer.On("AddTenants", mock.Anything, mock.Anything).Return(nil)
+				m.indexer.On("DeleteTenants", mock.Anything, mock.Anything).Return(nil)
+				m.indexer.On("UpdateTenants", mock.Anything, mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{
+						Class: cls, State: &sharding.State{PartitioningEnabled: true, Physical: map[string]sharding.Physical{
+							"T1": {Name: "T1", BelongsToNodes: []string{"Node-1"}},
+						}},
+					}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if _, ok := shardingState.Physical["T1"]; ok {
+					return fmt.Errorf("tenant T1 still exists")
+				}
+				if _, ok := shardingState.Physical["T3"]; !ok {
+					return fmt.Errorf("tenant T3 not found")
+				}
+				for _, call := range ms.indexer.Calls {
+					if call.Method != "AddTenants" {
+						continue
+					}
+					got := call.Arguments.Get(1).(*cmd.AddTenantsRequest).Tenants
+					if len(got) != 1 || got[0].Name != "T3" {
+						return fmt.Errorf("db tenants want: [T3] got: %v", got)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name: "UpdateTenants/Reason",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_UPDATE_TENANT,
+				nil, &cmd.UpdateTenantsRequest{
+					Tenants: []*cmd.Tenant{{Name: "T1", Status: models.TenantActivityStatusCOLD}},
+					Reason:  "idle",
+				})},
+			resp: Response{Error: nil},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.indexer.On("UpdateTenants", mock.Anything, mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{Class: cls, State: ss}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				history := ms.store.SchemaReader().CopyShardingState("C1").Physical["T1"].StatusHistory
+				if len(history) != 1 || history[0].Reason != "idle" {
+					return fmt.Errorf("status history want reason: idle got: %v", history)
+				}
+				return nil
+			},
+		},
+		{
+			name: "SetMaxTenantDeletes/Success",
+			req: raft.Log{Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_DELETE_TENANT,
+				nil, &cmd.DeleteTenantsRequest{Tenants: []string{"T1", "T2"}})},
+			resp: Response{Error: schema.ErrTooManyTenantDeletes},
+			doBefore: func(m *MockStore) {
+				doFirst(m)
+				m.indexer.On("AddClass", mock.Anything).Return(nil)
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_ADD_CLASS, cmd.AddClassRequest{
+						Class: cls, State: &sharding.State{
+							Physical: map[string]sharding.Physical{"T1": {}, "T2": {}},
+						},
+					}, nil),
+				})
+				m.store.Apply(&raft.Log{
+					Data: cmdAsBytes("C1", cmd.ApplyRequest_TYPE_SET_MAX_TENANT_DELETES,
+						cmd.SetMaxTenantDeletesRequest{Limit: 1}, nil),
+				})
+			},
+			doAfter: func(ms *MockStore) error {
+				shardingState := ms.store.SchemaReader().CopyShardingState("C1")
+				if len(shardingState.Physical) != 2 {
+					return fmt.Errorf("no tenant must be deleted above the limit")
+				}
+				for _, call := range ms.indexer.Calls {
+					if call.Method == "DeleteTenants" {
+						return fmt.Errorf("tenants must not be deleted from the db above the limit")
+					}
+				}
+				return nil
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -36,6 +36,9 @@ type MultiTenancyConfig struct {
 
 	// Whether or not multi-tenancy is enabled for this class
 	Enabled bool `json:"enabled"`
+
+	// Tenant names should (not) be normalized to lower case when tenants are created and looked up
+	NormalizeTenantNames bool `json:"normalizeTenantNames"`
 }
 
 // Validate validates this multi tenancy config
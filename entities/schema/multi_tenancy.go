@@ -11,7 +11,11 @@
 
 package schema
 
-import "github.com/weaviate/weaviate/entities/models"
+import (
+	"strings"
+
+	"github.com/weaviate/weaviate/entities/models"
+)
 
 func MultiTenancyEnabled(class *models.Class) bool {
 	if class == nil {
@@ -46,6 +50,26 @@ func AutoTenantActivationEnabled(class *models.Class) bool {
 	return false
 }
 
+func TenantNameNormalizationEnabled(class *models.Class) bool {
+	if class == nil {
+		return false
+	}
+
+	if class.MultiTenancyConfig != nil {
+		return class.MultiTenancyConfig.Enabled && class.MultiTenancyConfig.NormalizeTenantNames
+	}
+	return false
+}
+
+// NormalizeTenantName returns the name under which a tenant of class is stored.
+// It is the lower cased name if tenant name normalization is enabled for class.
+func NormalizeTenantName(class *models.Class, name string) string {
+	if TenantNameNormalizationEnabled(class) {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
 func ActivityStatus(status string) string {
 	if status == "" {
 		return models.TenantActivityStatusHOT
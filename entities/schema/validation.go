@@ -94,7 +94,7 @@ func ValidateNestedPropertyName(name, prefix string) error {
 func ValidateReservedPropertyName(name string) error {
 	for i := range reservedPropertyNames {
 		if name == reservedPropertyNames[i] {
-			return fmt.Errorf("'%s' is a reserved property name", name)
+			return fmt.Errorf("'%s' is a reserved property name, reserved names are %v", name, reservedPropertyNames)
 		}
 	}
 	return nil
@@ -376,6 +376,28 @@ func Test_DeleteAndRecreateS3Journey(t *testing.T) {
 			}, 5*time.Second, time.Second, fmt.Sprintf("tenant was never %s", models.TenantActivityStatusFROZEN))
 		})
 
+		t.Run("updating tenant status to COLD", func(t *testing.T) {
+			helper.UpdateTenants(t, className, []*models.Tenant{
+				{
+					Name:           tenantNames[0],
+					ActivityStatus: models.TenantActivityStatusCOLD,
+				},
+			})
+		})
+
+		t.Run("verify tenant status is COLD", func(t *testing.T) {
+			assert.EventuallyWithT(t, func(at *assert.CollectT) {
+				resp, err := helper.GetTenants(t, className)
+				require.Nil(t, err)
+				for _, tn := range resp.Payload {
+					if tn.Name == tenantNames[0] {
+						assert.Equal(at, models.TenantActivityStatusCOLD, tn.ActivityStatus)
+						break
+					}
+				}
+			}, 5*time.Second, time.Second, fmt.Sprintf("tenant was never %s", models.TenantActivityStatusCOLD))
+		})
+
 		t.Run("updating tenant status", func(t *testing.T) {
 			helper.UpdateTenants(t, className, []*models.Tenant{
 				{
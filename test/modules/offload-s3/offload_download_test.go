@@ -164,6 +164,28 @@ func Test_DownloadS3Journey(t *testing.T) {
 			require.NotNil(t, err)
 		})
 
+		t.Run("updating tenant status to COLD", func(t *testing.T) {
+			helper.UpdateTenants(t, className, []*models.Tenant{
+				{
+					Name:           tenantNames[0],
+					ActivityStatus: models.TenantActivityStatusCOLD,
+				},
+			})
+		})
+
+		t.Run("verify tenant status COLD", func(t *testing.T) {
+			assert.EventuallyWithT(t, func(at *assert.CollectT) {
+				resp, err := helper.GetTenantsGRPC(t, className)
+				require.Nil(t, err)
+				for _, tn := range resp.Tenants {
+					if tn.Name == tenantNames[0] {
+						assert.Equal(at, pb.TenantActivityStatus_TENANT_ACTIVITY_STATUS_COLD, tn.ActivityStatus)
+						break
+					}
+				}
+			}, 5*time.Second, time.Second, fmt.Sprintf("tenant was never %s", pb.TenantActivityStatus_TENANT_ACTIVITY_STATUS_COLD))
+		})
+
 		t.Run("updating tenant status to HOT", func(t *testing.T) {
 			helper.UpdateTenants(t, className, []*models.Tenant{
 				{
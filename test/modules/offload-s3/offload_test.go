@@ -182,6 +182,33 @@ func Test_Upload_DownloadS3Journey(t *testing.T) {
 			}, 5*time.Second, time.Second, fmt.Sprintf("tenant was never %s", models.TenantActivityStatusFROZEN))
 		})
 
+		t.Run("updating tenant status to COLD", func(t *testing.T) {
+			tenants := []*models.Tenant{}
+			for i := range tenantNames {
+				tenants = append(tenants, &models.Tenant{
+					Name:           tenantNames[i],
+					ActivityStatus: models.TenantActivityStatusCOLD,
+				})
+			}
+
+			helper.UpdateTenants(t, className, tenants)
+		})
+
+		t.Run("verify tenant status COLD", func(t *testing.T) {
+			assert.EventuallyWithT(t, func(at *assert.CollectT) {
+				resp, err := helper.GetTenants(t, className)
+				require.Nil(t, err)
+				for _, tn := range resp.Payload {
+					for i := range tenantNames {
+						if tn.Name == tenantNames[i] {
+							assert.Equal(at, models.TenantActivityStatusCOLD, tn.ActivityStatus)
+							break
+						}
+					}
+				}
+			}, 5*time.Second, time.Second, fmt.Sprintf("tenant was never %s", models.TenantActivityStatusCOLD))
+		})
+
 		t.Run("updating tenant status to HOT", func(t *testing.T) {
 			tenants := []*models.Tenant{}
 			for i := range tenantNames {
@@ -587,6 +614,35 @@ func Test_ConcurrentFreezeUnfreeze(t *testing.T) {
 		}
 	})
 
+	t.Run("updating tenant status to COLD", func(t *testing.T) {
+		tenants := []*models.Tenant{}
+		for i := range tenantNames {
+			tenants = append(tenants, &models.Tenant{
+				Name:           tenantNames[i],
+				ActivityStatus: models.TenantActivityStatusCOLD,
+			})
+		}
+
+		for idx := 0; idx < 5; idx++ {
+			go helper.UpdateTenants(t, className, tenants)
+		}
+	})
+
+	t.Run("verify tenant status COLD", func(t *testing.T) {
+		assert.EventuallyWithT(t, func(at *assert.CollectT) {
+			resp, err := helper.GetTenants(t, className)
+			require.Nil(t, err)
+			for _, tn := range resp.Payload {
+				for i := range tenantNames {
+					if tn.Name == tenantNames[i] {
+						assert.Equal(at, models.TenantActivityStatusCOLD, tn.ActivityStatus)
+						break
+					}
+				}
+			}
+		}, 5*time.Second, time.Second, fmt.Sprintf("tenant was never %s", models.TenantActivityStatusCOLD))
+	})
+
 	t.Run("updating tenant status to HOT", func(t *testing.T) {
 		tenants := []*models.Tenant{}
 		for i := range tenantNames {
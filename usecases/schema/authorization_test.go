@@ -126,6 +126,12 @@ func Test_Schema_Authorization(t *testing.T) {
 			expectedVerb:     "delete",
 			expectedResource: tenantsPath,
 		},
+		{
+			methodName:       "UpdateTenantReplicationFactor",
+			additionalArgs:   []interface{}{"className", "P1", int64(2)},
+			expectedVerb:     "update",
+			expectedResource: tenantsPath,
+		},
 		{
 			methodName:       "GetTenants",
 			additionalArgs:   []interface{}{"className"},
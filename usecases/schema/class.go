@@ -15,6 +15,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"reflect"
 	"strings"
@@ -113,6 +114,17 @@ func (h *Handler) AddClass(ctx context.Context, principal *models.Principal,
 	}
 
 	h.setClassDefaults(cls)
+	if !schema.MultiTenancyEnabled(cls) {
+		if err := applyShardingDefaults(cls, shardingcfg.Config{
+			VirtualPerPhysical: shardingcfg.DefaultVirtualPerPhysical,
+			DesiredCount:       h.clusterState.NodeCount(),
+			Key:                shardingcfg.DefaultKey,
+			Strategy:           shardingcfg.DefaultStrategy,
+			Function:           shardingcfg.DefaultFunction,
+		}); err != nil {
+			return nil, 0, err
+		}
+	}
 
 	if err := h.validateCanAddClass(ctx, cls, false); err != nil {
 		return nil, 0, err
@@ -243,12 +255,55 @@ func (h *Handler) UpdateClass(ctx context.Context, principal *models.Principal,
 		if err := validateImmutableFields(initial, updated); err != nil {
 			return err
 		}
+
+		// new properties are rejected by the parser, changed ones must still be consistent
+		existing := make(map[string]bool, len(initial.Properties))
+		for _, prop := range initial.Properties {
+			existing[prop.Name] = true
+		}
+		for _, prop := range updated.Properties {
+			if !existing[prop.Name] {
+				continue
+			}
+			if err := h.validateProperty(*prop); err != nil {
+				return err
+			}
+		}
 	}
 
 	_, err = h.schemaManager.UpdateClass(updated, shardingState)
 	return err
 }
 
+// applyShardingDefaults sets the keys of the raw sharding config of class which are not set yet
+// to the non-zero fields of defaults. Explicitly set keys are kept, so applying the defaults
+// more than once has no further effect. The config is parsed later on by the parser.
+func applyShardingDefaults(class *models.Class, defaults shardingcfg.Config) error {
+	var explicit map[string]interface{}
+	switch cfg := class.ShardingConfig.(type) {
+	case nil:
+		explicit = make(map[string]interface{}, 5)
+	case map[string]interface{}:
+		// the map may be shared with copies of the class and must not be modified
+		explicit = maps.Clone(cfg)
+	default:
+		return fmt.Errorf("unexpected sharding config type %T", cfg)
+	}
+
+	setDefault := func(key string, value interface{}, zero bool) {
+		if _, ok := explicit[key]; !ok && !zero {
+			explicit[key] = value
+		}
+	}
+	setDefault("virtualPerPhysical", defaults.VirtualPerPhysical, defaults.VirtualPerPhysical == 0)
+	setDefault("desiredCount", defaults.DesiredCount, defaults.DesiredCount == 0)
+	setDefault("key", defaults.Key, defaults.Key == "")
+	setDefault("strategy", defaults.Strategy, defaults.Strategy == "")
+	setDefault("function", defaults.Function, defaults.Function == "")
+	class.ShardingConfig = explicit
+	return nil
+}
+
 func (h *Handler) setClassDefaults(class *models.Class) {
 	// set only when no target vectors configured
 	if !hasTargetVectors(class) {
@@ -470,7 +525,7 @@ func migratePropertyIndexInverted(props ...*models.Property) {
 	}
 }
 
-func (h *Handler) validateProperty(
+func (h *Handler) validateProperties(
 	class *models.Class, existingPropertyNames map[string]bool,
 	relaxCrossRefValidation bool, props ...*models.Property,
 ) error {
@@ -498,18 +553,9 @@ func (h *Handler) validateProperty(
 			if err := validateNestedProperties(property.NestedProperties, property.Name); err != nil {
 				return err
 			}
-		} else {
-			if len(property.NestedProperties) > 0 {
-				return fmt.Errorf("property '%s': nestedProperties not allowed for data types other than object/object[]",
-					property.Name)
-			}
-		}
-
-		if err := h.validatePropertyTokenization(property.Tokenization, propertyDataType); err != nil {
-			return err
 		}
 
-		if err := h.validatePropertyIndexing(property); err != nil {
+		if err := h.validateProperty(*property); err != nil {
 			return err
 		}
 
@@ -521,6 +567,27 @@ func (h *Handler) validateProperty(
 	return nil
 }
 
+// validateProperty checks that the config flags of p (tokenization, nested properties and
+// index settings) are compatible with its data type. Referenced classes are not looked up.
+func (h *Handler) validateProperty(p models.Property) error {
+	propertyDataType, err := schema.FindPropertyDataTypeWithRefs(h.schemaReader.ReadOnlyClass, p.DataType,
+		true, "")
+	if err != nil {
+		return fmt.Errorf("property '%s': invalid dataType: %v", p.Name, err)
+	}
+
+	if !propertyDataType.IsNested() && len(p.NestedProperties) > 0 {
+		return fmt.Errorf("property '%s': nestedProperties not allowed for data types other than object/object[]",
+			p.Name)
+	}
+
+	if err := h.validatePropertyTokenization(p.Tokenization, propertyDataType); err != nil {
+		return err
+	}
+
+	return h.validatePropertyIndexing(&p)
+}
+
 func setInvertedConfigDefaults(class *models.Class) {
 	if class.InvertedIndexConfig == nil {
 		class.InvertedIndexConfig = &models.InvertedIndexConfig{}
@@ -554,7 +621,7 @@ func (h *Handler) validateCanAddClass(
 
 	existingPropertyNames := map[string]bool{}
 	for _, property := range class.Properties {
-		if err := h.validateProperty(class, existingPropertyNames, relaxCrossRefValidation, property); err != nil {
+		if err := h.validateProperties(class, existingPropertyNames, relaxCrossRefValidation, property); err != nil {
 			return err
 		}
 		existingPropertyNames[strings.ToLower(property.Name)] = true
@@ -735,6 +802,10 @@ func validateMT(class *models.Class) error {
 		return fmt.Errorf("can't enable autoTenantActivation on a non-multi-tenant class")
 	}
 
+	if !enabled && class.MultiTenancyConfig != nil && class.MultiTenancyConfig.NormalizeTenantNames {
+		return fmt.Errorf("can't enable normalizeTenantNames on a non-multi-tenant class")
+	}
+
 	return nil
 }
 
@@ -747,6 +818,8 @@ func validateUpdatingMT(current, update *models.Class) (enabled bool, err error)
 		} else {
 			err = fmt.Errorf("enabling multi-tenancy for an existing class is not supported")
 		}
+	} else if schema.TenantNameNormalizationEnabled(current) != schema.TenantNameNormalizationEnabled(update) {
+		err = fmt.Errorf("changing normalizeTenantNames for an existing class is not supported")
 	} else {
 		err = validateMT(update)
 	}
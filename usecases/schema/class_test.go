@@ -1309,6 +1309,25 @@ func Test_UpdateClass(t *testing.T) {
 				},
 				expectedError: nil,
 			},
+			{
+				name: "try to change tenant name normalization after creating the class",
+				initial: &models.Class{
+					Class:      "InitialName",
+					Vectorizer: "none",
+					MultiTenancyConfig: &models.MultiTenancyConfig{
+						Enabled: true,
+					},
+				},
+				update: &models.Class{
+					Class:      "InitialName",
+					Vectorizer: "none",
+					MultiTenancyConfig: &models.MultiTenancyConfig{
+						Enabled:              true,
+						NormalizeTenantNames: true,
+					},
+				},
+				expectedError: fmt.Errorf("changing normalizeTenantNames for an existing class is not supported"),
+			},
 			{
 				name: "change auto tenant activation after creating the class",
 				initial: &models.Class{
@@ -1328,6 +1347,24 @@ func Test_UpdateClass(t *testing.T) {
 				},
 				expectedError: nil,
 			},
+			{
+				name: "tokenization on a non-text property",
+				initial: &models.Class{
+					Class:      "InitialName",
+					Vectorizer: "none",
+					Properties: []*models.Property{
+						{Name: "count", DataType: schema.DataTypeInt.PropString()},
+					},
+				},
+				update: &models.Class{
+					Class:      "InitialName",
+					Vectorizer: "none",
+					Properties: []*models.Property{
+						{Name: "count", DataType: schema.DataTypeInt.PropString(), Tokenization: models.PropertyTokenizationWord},
+					},
+				},
+				expectedError: fmt.Errorf("Tokenization is not allowed for data type 'int'"),
+			},
 		}
 
 		for _, test := range tests {
@@ -1597,4 +1634,52 @@ func Test_AddClass_MultiTenancy(t *testing.T) {
 		_, _, err := handler.AddClass(ctx, nil, &class)
 		require.NotNil(t, err)
 	})
+
+	t.Run("with MT disabled, but tenant name normalization on", func(t *testing.T) {
+		handler, fakeSchemaManager := newTestHandler(t, &fakeDB{})
+		class := models.Class{
+			MultiTenancyConfig: &models.MultiTenancyConfig{Enabled: false, NormalizeTenantNames: true},
+			Class:              "NewClass",
+			Vectorizer:         "none",
+		}
+
+		fakeSchemaManager.On("AddClass", mock.Anything, mock.Anything).Return(nil)
+		_, _, err := handler.AddClass(ctx, nil, &class)
+		require.NotNil(t, err)
+	})
+}
+
+func Test_applyShardingDefaults(t *testing.T) {
+	defaults := shardingConfig.Config{
+		VirtualPerPhysical: 128,
+		DesiredCount:       3,
+		Key:                shardingConfig.DefaultKey,
+		Strategy:           shardingConfig.DefaultStrategy,
+		Function:           shardingConfig.DefaultFunction,
+	}
+
+	raw := map[string]interface{}{"desiredCount": float64(2)}
+	class := &models.Class{ShardingConfig: raw}
+	require.Nil(t, applyShardingDefaults(class, defaults))
+	want := map[string]interface{}{
+		"virtualPerPhysical": 128,
+		"desiredCount":       float64(2),
+		"key":                shardingConfig.DefaultKey,
+		"strategy":           shardingConfig.DefaultStrategy,
+		"function":           shardingConfig.DefaultFunction,
+	}
+	assert.Equal(t, want, class.ShardingConfig)
+	// the raw config may be shared with copies of the class
+	assert.Equal(t, map[string]interface{}{"desiredCount": float64(2)}, raw)
+
+	// idempotent
+	require.Nil(t, applyShardingDefaults(class, shardingConfig.Config{VirtualPerPhysical: 1, DesiredCount: 5}))
+	assert.Equal(t, want, class.ShardingConfig)
+
+	class.ShardingConfig = nil
+	require.Nil(t, applyShardingDefaults(class, shardingConfig.Config{DesiredCount: 3}))
+	assert.Equal(t, map[string]interface{}{"desiredCount": 3}, class.ShardingConfig)
+
+	class.ShardingConfig = "invalid"
+	assert.NotNil(t, applyShardingDefaults(class, defaults))
 }
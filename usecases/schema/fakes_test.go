@@ -70,6 +70,11 @@ func (f *fakeSchemaManager) UpdateTenants(class string, req *command.UpdateTenan
 	return 0, args.Error(0)
 }
 
+func (f *fakeSchemaManager) UpdateTenantReplicationFactor(class, tenant string, factor int64, nodes []string) (uint64, error) {
+	args := f.Called(class, tenant, factor, nodes)
+	return 0, args.Error(0)
+}
+
 func (f *fakeSchemaManager) DeleteTenants(class string, req *command.DeleteTenantsRequest) (uint64, error) {
 	args := f.Called(class, req)
 	return 0, args.Error(0)
@@ -46,6 +46,7 @@ type SchemaManager interface {
 	AddTenants(class string, req *command.AddTenantsRequest) (uint64, error)
 	UpdateTenants(class string, req *command.UpdateTenantsRequest) (uint64, error)
 	DeleteTenants(class string, req *command.DeleteTenantsRequest) (uint64, error)
+	UpdateTenantReplicationFactor(class, tenant string, factor int64, nodes []string) (uint64, error)
 
 	// Cluster related operations
 	Join(_ context.Context, nodeID, raftAddr string, voter bool) error
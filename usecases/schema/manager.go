@@ -334,6 +334,7 @@ func (m *Manager) ResolveParentNodes(class, shardName string) (map[string]string
 }
 
 func (m *Manager) TenantsShards(class string, tenants ...string) (map[string]string, error) {
+	tenants = m.normalizeTenantNames(class, tenants)
 	slices.Sort(tenants)
 	tenants = slices.Compact(tenants)
 	status, _, err := m.schemaManager.QueryTenantsShards(class, tenants...)
@@ -371,7 +372,8 @@ func (m *Manager) TenantsShards(class string, tenants ...string) (map[string]str
 func (m *Manager) OptimisticTenantStatus(class string, tenant string) (map[string]string, error) {
 	var foundTenant bool
 	var status string
-	err := m.schemaReader.Read(class, func(_ *models.Class, ss *sharding.State) error {
+	err := m.schemaReader.Read(class, func(c *models.Class, ss *sharding.State) error {
+		tenant = schema.NormalizeTenantName(c, tenant)
 		t, ok := ss.Physical[tenant]
 		if !ok {
 			return nil
@@ -396,6 +398,23 @@ func (m *Manager) OptimisticTenantStatus(class string, tenant string) (map[strin
 	}, nil
 }
 
+// normalizeTenantNames returns the names under which the specified tenants
+// are stored. It returns a new slice if names have to be normalized.
+func (m *Manager) normalizeTenantNames(class string, tenants []string) []string {
+	names := tenants
+	m.schemaReader.Read(class, func(c *models.Class, _ *sharding.State) error {
+		if !schema.TenantNameNormalizationEnabled(c) {
+			return nil
+		}
+		names = make([]string, len(tenants))
+		for i, t := range tenants {
+			names[i] = schema.NormalizeTenantName(c, t)
+		}
+		return nil
+	})
+	return names
+}
+
 func (m *Manager) activateTenantIfInactive(class string,
 	status map[string]string,
 ) (map[string]string, error) {
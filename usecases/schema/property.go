@@ -61,7 +61,7 @@ func (h *Handler) AddClassProperty(ctx context.Context, principal *models.Princi
 		}
 	}
 
-	if err := h.validateProperty(class, existingNames, false, newProps...); err != nil {
+	if err := h.validateProperties(class, existingNames, false, newProps...); err != nil {
 		return nil, 0, err
 	}
 
@@ -119,6 +119,64 @@ func TestHandler_AddProperty(t *testing.T) {
 	})
 }
 
+func TestHandler_AddProperty_IncompatibleConfig(t *testing.T) {
+	ctx := context.Background()
+	vTrue := true
+
+	props := []*models.Property{
+		{Name: "intSearchable", DataType: schema.DataTypeInt.PropString(), IndexSearchable: &vTrue},
+		{Name: "textRangeFilters", DataType: schema.DataTypeText.PropString(), IndexRangeFilters: &vTrue},
+		{Name: "textNested", DataType: schema.DataTypeText.PropString(), NestedProperties: []*models.NestedProperty{
+			{Name: "n", DataType: schema.DataTypeInt.PropString()},
+		}},
+		{Name: "_additional", DataType: schema.DataTypeText.PropString()},
+	}
+	for _, prop := range props {
+		t.Run(prop.Name, func(t *testing.T) {
+			handler, fakeSchemaManager := newTestHandler(t, &fakeDB{})
+			class := models.Class{Class: "NewClass", Vectorizer: "none"}
+
+			_, _, err := handler.AddClassProperty(ctx, nil, &class, false, prop)
+			require.Error(t, err)
+			fakeSchemaManager.AssertNotCalled(t, "AddProperty", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHandler_validateProperty(t *testing.T) {
+	handler, _ := newTestHandler(t, &fakeDB{})
+	vTrue, vFalse := true, false
+
+	tests := []struct {
+		name  string
+		prop  models.Property
+		valid bool
+	}{
+		{"text with tokenization", models.Property{Name: "p", DataType: schema.DataTypeText.PropString(), Tokenization: models.PropertyTokenizationWord}, true},
+		{"int with tokenization", models.Property{Name: "p", DataType: schema.DataTypeInt.PropString(), Tokenization: models.PropertyTokenizationWord}, false},
+		{"int searchable", models.Property{Name: "p", DataType: schema.DataTypeInt.PropString(), IndexSearchable: &vTrue}, false},
+		{"int not searchable", models.Property{Name: "p", DataType: schema.DataTypeInt.PropString(), IndexSearchable: &vFalse}, true},
+		{"text range filters", models.Property{Name: "p", DataType: schema.DataTypeText.PropString(), IndexRangeFilters: &vTrue}, false},
+		{"date range filters", models.Property{Name: "p", DataType: schema.DataTypeDate.PropString(), IndexRangeFilters: &vTrue}, true},
+		{"reference to unknown class", models.Property{Name: "p", DataType: []string{"Unknown"}}, true},
+		{"reference with tokenization", models.Property{Name: "p", DataType: []string{"Unknown"}, Tokenization: models.PropertyTokenizationWord}, false},
+		{"text with nested properties", models.Property{Name: "p", DataType: schema.DataTypeText.PropString(), NestedProperties: []*models.NestedProperty{
+			{Name: "n", DataType: schema.DataTypeInt.PropString()},
+		}}, false},
+		{"unknown data type", models.Property{Name: "p", DataType: []string{"unknown"}}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := handler.validateProperty(tc.prop)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
 // TestHandler_AddProperty_Object verifies that we can add properties on class with the Object and ObjectArray type.
 // This test is different than TestHandler_AddProperty because Object and ObjectArray require nested properties to be validated.
 func TestHandler_AddProperty_Object(t *testing.T) {
@@ -205,6 +205,24 @@ func (h *Handler) DeleteTenants(ctx context.Context, principal *models.Principal
 	return err
 }
 
+// UpdateTenantReplicationFactor overrides the class replication factor for a single tenant,
+// a factor of 0 removes the override. The factor can't exceed the number of cluster nodes.
+//
+// Class must exist and has partitioning enabled
+func (h *Handler) UpdateTenantReplicationFactor(ctx context.Context, principal *models.Principal,
+	class, tenant string, factor int64,
+) error {
+	if err := h.Authorizer.Authorize(principal, "update", tenantsPath); err != nil {
+		return err
+	}
+	if tenant == "" {
+		return fmt.Errorf("empty tenant name")
+	}
+
+	_, err := h.schemaManager.UpdateTenantReplicationFactor(class, tenant, factor, h.clusterState.Candidates())
+	return err
+}
+
 // GetTenants is used to get tenants of a class.
 //
 // Class must exist and has partitioning enabled
@@ -306,14 +324,15 @@ func (h *Handler) getTenantsByNames(class string, names []string) ([]*models.Ten
 	}
 
 	ts := make([]*models.Tenant, 0, len(names))
-	f := func(_ *models.Class, ss *sharding.State) error {
+	f := func(c *models.Class, ss *sharding.State) error {
 		for _, name := range names {
-			if _, ok := ss.Physical[name]; !ok {
+			physical, ok := ss.Physical[schema.NormalizeTenantName(c, name)]
+			if !ok {
 				continue
 			}
 			ts = append(ts, &models.Tenant{
 				Name:           name,
-				ActivityStatus: schema.ActivityStatus(ss.Physical[name].Status),
+				ActivityStatus: schema.ActivityStatus(physical.Status),
 			})
 		}
 		return nil
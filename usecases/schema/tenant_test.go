@@ -18,8 +18,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	clusterSchema "github.com/weaviate/weaviate/cluster/schema"
 	"github.com/weaviate/weaviate/entities/models"
 	"github.com/weaviate/weaviate/entities/schema"
+	"github.com/weaviate/weaviate/usecases/sharding"
 )
 
 func TestAddTenants(t *testing.T) {
@@ -453,3 +455,76 @@ func TestDeleteTenants(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateTenantReplicationFactor(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("EmptyTenant", func(t *testing.T) {
+		handler, fakeSchemaManager := newTestHandler(t, &fakeDB{})
+		assert.ErrorContains(t, handler.UpdateTenantReplicationFactor(ctx, nil, "C", "", 2), "empty tenant name")
+		fakeSchemaManager.AssertNotCalled(t, "UpdateTenantReplicationFactor", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		handler, fakeSchemaManager := newTestHandler(t, &fakeDB{})
+		nodes := handler.clusterState.Candidates()
+		fakeSchemaManager.On("UpdateTenantReplicationFactor", "C", "T1", int64(2), nodes).Return(nil)
+		require.NoError(t, handler.UpdateTenantReplicationFactor(ctx, nil, "C", "T1", 2))
+		fakeSchemaManager.AssertExpectations(t)
+	})
+}
+
+func TestTenantNameNormalization(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		class = &models.Class{
+			Class: "C",
+			MultiTenancyConfig: &models.MultiTenancyConfig{
+				Enabled:              true,
+				NormalizeTenantNames: true,
+			},
+		}
+		ss = &sharding.State{
+			PartitioningEnabled: true,
+			Physical: map[string]sharding.Physical{
+				"tenant1": {Name: "tenant1", Status: models.TenantActivityStatusHOT},
+			},
+		}
+	)
+
+	newManager := func(t *testing.T) (*Manager, *fakeSchemaManager) {
+		handler, fakeSchemaManager := newTestHandler(t, &fakeDB{})
+		fakeSchemaManager.On("Read", class.Class, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(1).(func(*models.Class, *sharding.State) error)(class, ss)
+		}).Return(nil)
+		fakeSchemaManager.On("ClassInfo", class.Class).Return(clusterSchema.ClassInfo{
+			Exists:       true,
+			MultiTenancy: *class.MultiTenancyConfig,
+			Tenants:      len(ss.Physical),
+		})
+		return &Manager{Handler: *handler}, fakeSchemaManager
+	}
+
+	t.Run("OptimisticTenantStatus", func(t *testing.T) {
+		m, _ := newManager(t)
+		status, err := m.OptimisticTenantStatus(class.Class, "Tenant1")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"tenant1": models.TenantActivityStatusHOT}, status)
+	})
+
+	t.Run("TenantsShards", func(t *testing.T) {
+		m, fakeSchemaManager := newManager(t)
+		fakeSchemaManager.On("QueryTenantsShards", class.Class, []string{"tenant1"}).
+			Return(nil, "tenant1")
+		status, err := m.TenantsShards(class.Class, "TENANT1")
+		require.NoError(t, err)
+		assert.Contains(t, status, "tenant1")
+		fakeSchemaManager.AssertCalled(t, "QueryTenantsShards", class.Class, []string{"tenant1"})
+	})
+
+	t.Run("ConsistentTenantExists", func(t *testing.T) {
+		m, _ := newManager(t)
+		require.NoError(t, m.ConsistentTenantExists(ctx, nil, class.Class, false, "Tenant1"))
+		require.ErrorIs(t, m.ConsistentTenantExists(ctx, nil, class.Class, false, "Tenant2"), ErrNotFound)
+	})
+}
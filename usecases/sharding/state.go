@@ -68,6 +68,42 @@ type Physical struct {
 	BelongsToNodes                       []string `json:"belongsToNodes,omitempty"`
 
 	Status string `json:"status,omitempty"`
+
+	// ReplicationFactor overrides the class replication factor for this
+	// shard. Zero means the class replication factor applies.
+	ReplicationFactor int64 `json:"replicationFactor,omitempty"`
+
+	// Metadata holds user defined labels of the shard
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// LastModifiedUnix is the time in unix milliseconds the shard was created or its status last changed.
+	// It is zero for shards which weren't modified since it was recorded.
+	LastModifiedUnix int64 `json:"lastModifiedUnix,omitempty"`
+
+	// StatusHistory holds the most recent status changes of the shard, oldest first
+	StatusHistory []StatusChange `json:"statusHistory,omitempty"`
+}
+
+// MaxStatusHistory is the maximum number of status changes kept per shard
+const MaxStatusHistory = 16
+
+// StatusChange records a single status change of a shard
+type StatusChange struct {
+	Status string `json:"status"`
+	// Reason is the reason given for the change, if any
+	Reason string `json:"reason,omitempty"`
+	// ModifiedUnix is the time of the change in unix milliseconds
+	ModifiedUnix int64 `json:"modifiedUnix,omitempty"`
+}
+
+// AddStatusChange appends change to the status history of p, dropping the oldest
+// changes beyond MaxStatusHistory. The history is copied, so copies of p aren't affected.
+func (p *Physical) AddStatusChange(change StatusChange) {
+	history := p.StatusHistory
+	if len(history) >= MaxStatusHistory {
+		history = history[len(history)-MaxStatusHistory+1:]
+	}
+	p.StatusHistory = append(append(make([]StatusChange, 0, len(history)+1), history...), change)
 }
 
 // BelongsToNode for backward-compatibility when there was no replication. It
@@ -508,12 +544,30 @@ func (p Physical) DeepCopy() Physical {
 	belongsCopy := make([]string, len(p.BelongsToNodes))
 	copy(belongsCopy, p.BelongsToNodes)
 
+	var metadataCopy map[string]string
+	if len(p.Metadata) > 0 {
+		metadataCopy = make(map[string]string, len(p.Metadata))
+		for k, v := range p.Metadata {
+			metadataCopy[k] = v
+		}
+	}
+
+	var statusHistoryCopy []StatusChange
+	if len(p.StatusHistory) > 0 {
+		statusHistoryCopy = make([]StatusChange, len(p.StatusHistory))
+		copy(statusHistoryCopy, p.StatusHistory)
+	}
+
 	return Physical{
-		Name:           p.Name,
-		OwnsVirtual:    ownsVirtualCopy,
-		OwnsPercentage: p.OwnsPercentage,
-		BelongsToNodes: belongsCopy,
-		Status:         p.Status,
+		Name:              p.Name,
+		OwnsVirtual:       ownsVirtualCopy,
+		OwnsPercentage:    p.OwnsPercentage,
+		BelongsToNodes:    belongsCopy,
+		Status:            p.Status,
+		ReplicationFactor: p.ReplicationFactor,
+		Metadata:          metadataCopy,
+		LastModifiedUnix:  p.LastModifiedUnix,
+		StatusHistory:     statusHistoryCopy,
 	}
 }
 
@@ -326,11 +326,15 @@ func TestStateDeepCopy(t *testing.T) {
 		localNodeName: "original",
 		Physical: map[string]Physical{
 			"physical1": {
-				Name:           "original",
-				OwnsVirtual:    []string{"original"},
-				OwnsPercentage: 7,
-				BelongsToNodes: []string{"original"},
-				Status:         models.TenantActivityStatusHOT,
+				Name:              "original",
+				OwnsVirtual:       []string{"original"},
+				OwnsPercentage:    7,
+				BelongsToNodes:    []string{"original"},
+				Status:            models.TenantActivityStatusHOT,
+				ReplicationFactor: 3,
+				Metadata:          map[string]string{"original": "original"},
+				LastModifiedUnix:  10,
+				StatusHistory:     []StatusChange{{Status: models.TenantActivityStatusHOT, Reason: "original"}},
 			},
 		},
 		Virtual: []Virtual{
@@ -358,11 +362,15 @@ func TestStateDeepCopy(t *testing.T) {
 		localNodeName: "original",
 		Physical: map[string]Physical{
 			"physical1": {
-				Name:           "original",
-				OwnsVirtual:    []string{"original"},
-				OwnsPercentage: 7,
-				BelongsToNodes: []string{"original"},
-				Status:         models.TenantActivityStatusHOT,
+				Name:              "original",
+				OwnsVirtual:       []string{"original"},
+				OwnsPercentage:    7,
+				BelongsToNodes:    []string{"original"},
+				Status:            models.TenantActivityStatusHOT,
+				ReplicationFactor: 3,
+				Metadata:          map[string]string{"original": "original"},
+				LastModifiedUnix:  10,
+				StatusHistory:     []StatusChange{{Status: models.TenantActivityStatusHOT, Reason: "original"}},
 			},
 		},
 		Virtual: []Virtual{
@@ -397,6 +405,11 @@ func TestStateDeepCopy(t *testing.T) {
 	physical1.OwnsPercentage = 100
 	physical1.OwnsVirtual = append(physical1.OwnsVirtual, "changed")
 	physical1.Status = models.TenantActivityStatusCOLD
+	physical1.ReplicationFactor = 5
+	physical1.Metadata["original"] = "changed"
+	physical1.Metadata["changed"] = "changed"
+	physical1.LastModifiedUnix = 20
+	physical1.StatusHistory[0].Reason = "changed"
 	copied.Physical["physical1"] = physical1
 	copied.Physical["physical2"] = Physical{}
 	copied.Virtual[0].Name = "original"